@@ -0,0 +1,24 @@
+// Package failure provides the error type the packages of this module share
+// Every package declares its own reasons as sentinel errors and reports them wrapped in an
+// Error, which it exposes under its own name, like vector.Error.
+package failure
+
+// Error tells which operation failed and why
+type Error struct {
+	Op     string // the operation, like "vector.NewVector"
+	Err    error  // the reason, one of the sentinel errors of the package
+	Detail string // what was found instead, may be empty
+}
+
+// Error implements the error interface
+func (e *Error) Error() string {
+	if e.Detail == "" {
+		return e.Op + ": " + e.Err.Error()
+	}
+	return e.Op + ": " + e.Err.Error() + ", " + e.Detail
+}
+
+// Unwrap gives the reason, so errors.Is works
+func (e *Error) Unwrap() error {
+	return e.Err
+}
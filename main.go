@@ -1,6 +1,7 @@
 package main
 
 import (
+	"image/color"
 	"log"
 	"time"
 
@@ -56,6 +57,10 @@ func main() {
 	camera := render.NewCamera(
 		vector.NewVector([]float32{0.0, -300.0, 0.0}),
 		vector.NewVector([]float32{0.0, 0.0, 0.0}))
+	camera.SetAspect(float32(winWidth) / float32(winHeight))
+	lighting := render.NewLighting(render.GouraudShading,
+		render.NewAmbientLight(color.White, 0.2),
+		render.NewDirectionalLight(vector.NewVector([]float32{-1.0, 2.0, -3.0}), color.White, 0.8))
 
 	// Let's define a simple box arround the origin
 	box := model.NewBox(100.0, 100.0, 100.0)
@@ -79,7 +84,7 @@ func main() {
 		box.SetRotation(rotate)
 
 		// Draw box 2.0
-		render.Draw(box.GetMeshes(), camera, canvas)
+		render.Draw(box.GetMeshes(), camera, lighting, canvas)
 
 		// Update rotation 2deg per frame
 		angle += 2.0
@@ -0,0 +1,118 @@
+package model
+
+import (
+	"log"
+	"reflect"
+	"sort"
+
+	"../number/quaternion"
+	"../number/vector"
+)
+
+// Path selects the property of a part an animation channel changes
+type Path int
+
+const (
+	// Translation animates the position, with 3D values
+	Translation Path = iota
+	// Rotation animates the orientation, with 4D unit quaternion values (x, y, z, w)
+	Rotation
+	// Scale animates the scale, with 3D values
+	Scale
+)
+
+// Animation moves parts of a model over time
+type Animation struct {
+	name     string
+	channels []channel
+}
+
+// channel animates a single property of a single part using key frames
+type channel struct {
+	part   *Part
+	path   Path
+	times  []float32
+	values []vector.Vector
+	step   bool
+}
+
+// NewAnimation creates an empty animation
+func NewAnimation(name string) *Animation {
+	return &Animation{name: name}
+}
+
+// GetName returns the name of the animation
+func (a *Animation) GetName() string {
+	return a.name
+}
+
+// AddChannel makes the animation change one property of a part
+// The values are key frames at the given times, in seconds and increasing. In between
+// the values are interpolated linearly (spherical for rotations) unless step is set,
+// in which case a value holds until the next key frame.
+func (a *Animation) AddChannel(part *Part, path Path, times []float32, values []vector.Vector, step bool) {
+	if part == nil {
+		log.Fatalf("Animation.AddChannel: part can't be nil")
+	}
+	if len(times) == 0 || len(times) != len(values) {
+		log.Fatalf("Animation.AddChannel: expects as many times as values, got %d and %d", len(times), len(values))
+	}
+	if !sort.SliceIsSorted(times, func(i, j int) bool { return times[i] < times[j] }) {
+		log.Fatalf("Animation.AddChannel: times must be increasing")
+	}
+	size := 3
+	if path == Rotation {
+		size = 4
+	}
+	for _, v := range values {
+		if v.Len() != size || v.Kind() != reflect.Float32 {
+			log.Fatalf("Animation.AddChannel: expects %dD-Float32 values, got %dD-%v", size, v.Len(), v.Kind())
+		}
+	}
+	a.channels = append(a.channels, channel{part, path, times, values, step})
+}
+
+// Duration returns the time of the last key frame
+func (a *Animation) Duration() float32 {
+	duration := float32(0.0)
+	for _, c := range a.channels {
+		if last := c.times[len(c.times)-1]; last > duration {
+			duration = last
+		}
+	}
+	return duration
+}
+
+// Apply sets the animated properties of the parts to their values at time t
+// Before the first and after the last key frame the values hold still.
+func (a *Animation) Apply(t float32) {
+	for _, c := range a.channels {
+		value := c.sample(t)
+		switch c.path {
+		case Translation:
+			c.part.SetPosition(value)
+		case Rotation:
+			c.part.SetOrientation(quaternion.FromVector(value))
+		case Scale:
+			c.part.SetScale(value)
+		}
+	}
+}
+
+// sample provides the value of a channel at time t
+func (c channel) sample(t float32) vector.Vector {
+	next := sort.Search(len(c.times), func(i int) bool { return c.times[i] > t })
+	switch {
+	case next == 0:
+		return c.values[0]
+	case next == len(c.times) || c.step:
+		return c.values[next-1]
+	}
+
+	f := (t - c.times[next-1]) / (c.times[next] - c.times[next-1])
+	if c.path == Rotation {
+		return quaternion.FromVector(c.values[next-1]).Slerp(quaternion.FromVector(c.values[next]), f).Vector()
+	}
+	from := c.values[next-1]
+	return from.Add(c.values[next].Sub(from).Muls(f))
+}
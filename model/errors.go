@@ -0,0 +1,22 @@
+package model
+
+import (
+	"errors"
+
+	"../internal/failure"
+)
+
+// The reasons a part of the model can't be made, check for them with errors.Is
+var (
+	ErrVertexCount  = errors.New("expected 3 points")
+	ErrDimension    = errors.New("expected 3D points")
+	ErrKind         = errors.New("expected Float32 points")
+	ErrSize         = errors.New("must have positive sizes")
+	ErrTessellation = errors.New("too few segments, rings or subdivisions")
+	ErrTransform    = errors.New("expected a 3x3-Float32 matrix")
+	ErrSingular     = errors.New("transformation is singular")
+)
+
+// Error tells which part of the model couldn't be made and why, get it with errors.As
+// Defects in the triangles of a part are reported as *MeshError instead.
+type Error = failure.Error
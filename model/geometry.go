@@ -0,0 +1,304 @@
+package model
+
+import (
+	"fmt"
+	"image/color"
+	"log"
+	"math"
+	"reflect"
+
+	"../number/matrix"
+	"../number/vector"
+)
+
+// Geometry is an indexed triangle list: a vertex array and triangles refering to it
+// Vertices shared by several triangles are stored, and transformed, only once. Vertices can
+// have a normal, texture coordinates and a color, like the vertices of a Mesh.
+type Geometry struct {
+	vertices  []vector.Vector
+	normals   []vector.Vector // may be shorter than vertices, missing ones are nil
+	uvs       []vector.Vector
+	colors    []color.Color
+	triangles []triangle
+	shared    map[vertexKey]int // vertices added by AddMesh, to share them
+}
+
+// vertexKey identifies a vertex with all its attributes
+type vertexKey struct {
+	position [3]float32
+	normal   [3]float32
+	uv       [2]float32
+	color    [4]uint32
+	has      [3]bool
+}
+
+// triangle holds the indices of the corners of a triangle
+type triangle struct {
+	indices  [3]int
+	material *Material // nil uses the material of the part
+}
+
+// NewGeometry creates an empty Geometry
+func NewGeometry() *Geometry {
+	return &Geometry{}
+}
+
+// AddVertex adds a vertex and returns its index
+func (g *Geometry) AddVertex(vertex vector.Vector) int {
+	if vertex.Len() != 3 || vertex.Kind() != reflect.Float32 {
+		log.Fatalf("Geometry.AddVertex: expects 3D-Float32 vector, got %dD-%v", vertex.Len(), vertex.Kind())
+	}
+	g.vertices = append(g.vertices, vertex)
+	return len(g.vertices) - 1
+}
+
+// AddTriangle adds a triangle between three vertices, counter-clockwise seen from the front
+// The material may be nil to use that of the part.
+func (g *Geometry) AddTriangle(a int, b int, c int, material *Material) {
+	for _, i := range []int{a, b, c} {
+		if i < 0 || i >= len(g.vertices) {
+			log.Fatalf("Geometry.AddTriangle: index out of range %d, have %d", i, len(g.vertices))
+		}
+	}
+	g.triangles = append(g.triangles, triangle{[3]int{a, b, c}, material})
+}
+
+// SetNormal sets the 3D normal of a vertex, nil removes it
+func (g *Geometry) SetNormal(index int, normal vector.Vector) {
+	g.checkIndex("Geometry.SetNormal", index)
+	if normal != nil && (normal.Len() != 3 || normal.Kind() != reflect.Float32) {
+		log.Fatalf("Geometry.SetNormal: expects 3D-Float32 vector, got %dD-%v", normal.Len(), normal.Kind())
+	}
+	for len(g.normals) <= index {
+		g.normals = append(g.normals, nil)
+	}
+	g.normals[index] = normal
+}
+
+// GetNormal returns the normal of a vertex, nil if it has none
+func (g *Geometry) GetNormal(index int) vector.Vector {
+	g.checkIndex("Geometry.GetNormal", index)
+	if index >= len(g.normals) {
+		return nil
+	}
+	return g.normals[index]
+}
+
+// SetUV sets the 2D texture coordinates of a vertex, nil removes them
+func (g *Geometry) SetUV(index int, uv vector.Vector) {
+	g.checkIndex("Geometry.SetUV", index)
+	if uv != nil && (uv.Len() != 2 || uv.Kind() != reflect.Float32) {
+		log.Fatalf("Geometry.SetUV: expects 2D-Float32 vector, got %dD-%v", uv.Len(), uv.Kind())
+	}
+	for len(g.uvs) <= index {
+		g.uvs = append(g.uvs, nil)
+	}
+	g.uvs[index] = uv
+}
+
+// GetUV returns the texture coordinates of a vertex, nil if it has none
+func (g *Geometry) GetUV(index int) vector.Vector {
+	g.checkIndex("Geometry.GetUV", index)
+	if index >= len(g.uvs) {
+		return nil
+	}
+	return g.uvs[index]
+}
+
+// SetColor sets the color of a vertex, nil removes it
+func (g *Geometry) SetColor(index int, color color.Color) {
+	g.checkIndex("Geometry.SetColor", index)
+	for len(g.colors) <= index {
+		g.colors = append(g.colors, nil)
+	}
+	g.colors[index] = color
+}
+
+// GetColor returns the color of a vertex, nil if it has none
+func (g *Geometry) GetColor(index int) color.Color {
+	g.checkIndex("Geometry.GetColor", index)
+	if index >= len(g.colors) {
+		return nil
+	}
+	return g.colors[index]
+}
+
+// checkIndex stops on a vertex index that is out of range
+func (g *Geometry) checkIndex(caller string, index int) {
+	if index < 0 || index >= len(g.vertices) {
+		log.Fatalf("%s: index out of range %d", caller, index)
+	}
+}
+
+// AddMesh adds a Mesh as a triangle, sharing the vertices with earlier meshes at the same spot
+// with the same attributes
+func (g *Geometry) AddMesh(mesh Mesh) {
+	if g.shared == nil {
+		g.shared = make(map[vertexKey]int)
+	}
+	var indices [3]int
+	for i := range indices {
+		vertex, normal, uv, color := mesh.GetVertex(i), mesh.GetNormal(i), mesh.GetUV(i), mesh.GetColor(i)
+		key := vertexKey{position: [3]float32{vertex.Get(0).(float32), vertex.Get(1).(float32), vertex.Get(2).(float32)}}
+		if normal != nil {
+			key.normal = [3]float32{normal.Get(0).(float32), normal.Get(1).(float32), normal.Get(2).(float32)}
+			key.has[0] = true
+		}
+		if uv != nil {
+			key.uv = [2]float32{uv.Get(0).(float32), uv.Get(1).(float32)}
+			key.has[1] = true
+		}
+		if color != nil {
+			r, gr, b, a := color.RGBA()
+			key.color = [4]uint32{r, gr, b, a}
+			key.has[2] = true
+		}
+
+		index, ok := g.shared[key]
+		if !ok {
+			index = g.AddVertex(vertex)
+			if normal != nil {
+				g.SetNormal(index, normal)
+			}
+			if uv != nil {
+				g.SetUV(index, uv)
+			}
+			if color != nil {
+				g.SetColor(index, color)
+			}
+			g.shared[key] = index
+		}
+		indices[i] = index
+	}
+	g.triangles = append(g.triangles, triangle{indices, mesh.material})
+}
+
+// VertexCount returns the number of vertices
+func (g *Geometry) VertexCount() int {
+	return len(g.vertices)
+}
+
+// TriangleCount returns the number of triangles
+func (g *Geometry) TriangleCount() int {
+	return len(g.triangles)
+}
+
+// GetVertex returns a vertex
+func (g *Geometry) GetVertex(index int) vector.Vector {
+	if index < 0 || index >= len(g.vertices) {
+		log.Fatalf("Geometry.GetVertex: index out of range %d", index)
+	}
+	return g.vertices[index]
+}
+
+// GetTriangle returns the vertex indices of a triangle
+func (g *Geometry) GetTriangle(index int) [3]int {
+	if index < 0 || index >= len(g.triangles) {
+		log.Fatalf("Geometry.GetTriangle: index out of range %d", index)
+	}
+	return g.triangles[index].indices
+}
+
+// GetTriangleMaterial returns the Material of a triangle, nil if it uses that of the part
+func (g *Geometry) GetTriangleMaterial(index int) *Material {
+	if index < 0 || index >= len(g.triangles) {
+		log.Fatalf("Geometry.GetTriangleMaterial: index out of range %d", index)
+	}
+	return g.triangles[index].material
+}
+
+// Meshes provides the triangles as separate meshes
+// A mesh only gets an attribute when all three of its vertices have it.
+func (g *Geometry) Meshes() []Mesh {
+	meshes := make([]Mesh, len(g.triangles))
+	for i, t := range g.triangles {
+		a, b, c := t.indices[0], t.indices[1], t.indices[2]
+		meshes[i].vertices = [3]vector.Vector{g.vertices[a], g.vertices[b], g.vertices[c]}
+		meshes[i].material = t.material
+		if n := []vector.Vector{g.GetNormal(a), g.GetNormal(b), g.GetNormal(c)}; n[0] != nil && n[1] != nil && n[2] != nil {
+			meshes[i].normals = n
+		}
+		if uv := []vector.Vector{g.GetUV(a), g.GetUV(b), g.GetUV(c)}; uv[0] != nil && uv[1] != nil && uv[2] != nil {
+			meshes[i].uvs = uv
+		}
+		if color := []color.Color{g.GetColor(a), g.GetColor(b), g.GetColor(c)}; color[0] != nil && color[1] != nil && color[2] != nil {
+			meshes[i].colors = color
+		}
+	}
+	return meshes
+}
+
+// append adds the geometry to result, every vertex transformed once by the 4x4 transform
+// Normals are transformed by the inverse-transpose, so they stay perpendicular to the surface.
+// Triangles without a material get the given one.
+func (g *Geometry) append(transform matrix.Matrix, material *Material, result *Geometry) {
+	base := len(result.vertices)
+	for _, v := range g.vertices {
+		result.vertices = append(result.vertices, vector.PerspectiveDivide(transform.Mulv(vector.Homogeneous(v))))
+	}
+	if len(g.normals) > 0 {
+		normalTransform := normalMatrix(matrix.Linear(transform))
+		for i, n := range g.normals {
+			if n == nil {
+				continue
+			}
+			if n = normalTransform.Mulv(n); n.Abs() != 0.0 {
+				n = n.Unit()
+			}
+			result.SetNormal(base+i, n)
+		}
+	}
+	for i, uv := range g.uvs {
+		if uv != nil {
+			result.SetUV(base+i, uv)
+		}
+	}
+	for i, c := range g.colors {
+		if c != nil {
+			result.SetColor(base+i, c)
+		}
+	}
+	for _, t := range g.triangles {
+		this := triangle{[3]int{t.indices[0] + base, t.indices[1] + base, t.indices[2] + base}, t.material}
+		if this.material == nil {
+			this.material = material
+		}
+		result.triangles = append(result.triangles, this)
+	}
+}
+
+// normalMatrix provides the inverse-transpose of a 3x3 transformation, up to its length
+// The columns of the cofactor matrix are the cross products of the columns of the transformation,
+// this works for singular transformations as well. Mirroring flips the sign to keep normals outside.
+func normalMatrix(transform matrix.Matrix) matrix.Matrix {
+	var m [3][3]float64
+	for r := 0; r < 3; r++ {
+		for c := 0; c < 3; c++ {
+			m[r][c] = float64(transform.Get(r, c).(float32))
+		}
+	}
+
+	var cofactor [3][3]float64
+	for c := 0; c < 3; c++ {
+		a, b := (c+1)%3, (c+2)%3
+		cofactor[0][c] = m[1][a]*m[2][b] - m[2][a]*m[1][b]
+		cofactor[1][c] = m[2][a]*m[0][b] - m[0][a]*m[2][b]
+		cofactor[2][c] = m[0][a]*m[1][b] - m[1][a]*m[0][b]
+	}
+	det := m[0][0]*cofactor[0][0] + m[1][0]*cofactor[1][0] + m[2][0]*cofactor[2][0]
+	sign := math.Copysign(1.0, det)
+
+	result := make([][]float32, 3)
+	for r := range result {
+		result[r] = make([]float32, 3)
+		for c := range result[r] {
+			result[r][c] = float32(sign * cofactor[r][c])
+		}
+	}
+	return matrix.NewMatrix(result)
+}
+
+// Add stringer interface
+func (g *Geometry) String() string {
+	return fmt.Sprintf("Geometry{%d vertices, %d triangles}", len(g.vertices), len(g.triangles))
+}
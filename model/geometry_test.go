@@ -0,0 +1,87 @@
+package model
+
+import (
+	"image/color"
+	"testing"
+
+	"../number/vector"
+)
+
+func Test_Geometry(t *testing.T) {
+	// The corners of a box are shared, also after transforming
+	box := NewBox(2.0, 2.0, 2.0)
+	box.SetPosition(vector.NewVector([]float32{1.0, 0.0, 0.0}))
+	if geometry := box.GetGeometry(); geometry.VertexCount() != 8 || geometry.TriangleCount() != 12 {
+		t.Fatalf("Expected 8 vertices and 12 triangles, got %v", geometry)
+	}
+	top := NewBox(1.0, 1.0, 1.0)
+	box.AddPart("top", &top.Part)
+	geometry := box.GetTransformedGeometry()
+	if geometry.VertexCount() != 16 || geometry.TriangleCount() != 24 {
+		t.Fatalf("Expected 16 vertices and 24 triangles, got %v", geometry)
+	}
+	if triangle := geometry.GetTriangle(12); triangle != [3]int{8, 11, 9} {
+		t.Errorf("Expected the top to use its own vertices, got %v", triangle)
+	}
+	if !near(geometry.GetVertex(8), vector.NewVector([]float32{0.5, 0.0, -0.5})) {
+		t.Errorf("Expected (0.5, 0, -0.5), got %v", geometry.GetVertex(8))
+	}
+
+	// The mesh view matches the triangles
+	meshes := box.GetMeshes()
+	if len(meshes) != 24 || !meshes[12].GetVertex(0).Equal(geometry.GetVertex(8)) {
+		t.Errorf("Expected 24 meshes starting the top at %v, got %v", geometry.GetVertex(8), meshes[12])
+	}
+
+	// Meshes share the vertices they have in common
+	green := DefaultMaterial()
+	var shared Geometry
+	a := vector.NewVector([]float32{0.0, 0.0, 0.0})
+	b := vector.NewVector([]float32{1.0, 0.0, 0.0})
+	c := vector.NewVector([]float32{0.0, 1.0, 0.0})
+	d := vector.NewVector([]float32{1.0, 1.0, 0.0})
+	shared.AddMesh(NewMesh([]vector.Vector{a, b, c}))
+	shared.AddMesh(NewMesh([]vector.Vector{b, d, c}).SetMaterial(green))
+	if shared.VertexCount() != 4 || shared.GetTriangle(1) != [3]int{1, 3, 2} || shared.GetTriangleMaterial(1) != green {
+		t.Errorf("Expected 4 shared vertices, got %v %v", &shared, shared.GetTriangle(1))
+	}
+}
+
+func Test_VertexAttributes(t *testing.T) {
+	red := color.NRGBA{0xff, 0x00, 0x00, 0xff}
+	mesh := NewMesh([]vector.Vector{
+		vector.NewVector([]float32{0.0, 0.0, 0.0}),
+		vector.NewVector([]float32{1.0, 0.0, 0.0}),
+		vector.NewVector([]float32{0.0, 1.0, 0.0}),
+	})
+	normal := vector.NewVector([]float32{1.0, 1.0, 0.0}).Unit()
+	uv := vector.NewVector([]float32{0.25, 0.75})
+	mesh = mesh.SetNormals([]vector.Vector{normal, normal, normal}).
+		SetUVs([]vector.Vector{uv, uv, uv}).
+		SetColors([]color.Color{red, red, red})
+
+	// Stretching along x tilts the normal towards y
+	part := &Part{}
+	part.GetGeometry().AddMesh(mesh)
+	part.SetScale(vector.NewVector([]float32{2.0, 1.0, 1.0}))
+	meshes := part.GetMeshes()
+	expected := vector.NewVector([]float32{0.5, 1.0, 0.0}).Unit()
+	if n := meshes[0].GetNormal(1); n == nil || !near(n, expected) {
+		t.Errorf("Expected normal %v, got %v", expected, n)
+	}
+	if !meshes[0].GetUV(2).Equal(uv) || meshes[0].GetColor(0) != red {
+		t.Errorf("Expected uv %v and color %v, got %v and %v", uv, red, meshes[0].GetUV(2), meshes[0].GetColor(0))
+	}
+
+	// Mirroring keeps the normal on the outside
+	part.SetScale(vector.NewVector([]float32{-1.0, 1.0, 1.0}))
+	expected = vector.NewVector([]float32{-1.0, 1.0, 0.0}).Unit()
+	if n := part.GetMeshes()[0].GetNormal(0); !near(n, expected) {
+		t.Errorf("Expected normal %v, got %v", expected, n)
+	}
+
+	// Without attributes there is nothing to get
+	if plain := NewMesh([]vector.Vector{normal, normal, normal}); plain.GetNormal(0) != nil || plain.GetUV(0) != nil || plain.GetColor(0) != nil {
+		t.Errorf("Expected no attributes, got %v", plain)
+	}
+}
@@ -0,0 +1,1094 @@
+package model
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"io"
+	"math"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"../number/matrix"
+	"../number/quaternion"
+	"../number/vector"
+)
+
+// The parts of a glTF 2.0 document we support
+type gltfDocument struct {
+	Asset       gltfAsset        `json:"asset"`
+	Scene       *int             `json:"scene,omitempty"`
+	Scenes      []gltfScene      `json:"scenes,omitempty"`
+	Nodes       []gltfNode       `json:"nodes,omitempty"`
+	Meshes      []gltfMesh       `json:"meshes,omitempty"`
+	Materials   []gltfMaterial   `json:"materials,omitempty"`
+	Accessors   []gltfAccessor   `json:"accessors,omitempty"`
+	BufferViews []gltfBufferView `json:"bufferViews,omitempty"`
+	Buffers     []gltfBuffer     `json:"buffers,omitempty"`
+	Animations  []gltfAnimation  `json:"animations,omitempty"`
+}
+
+type gltfAsset struct {
+	Version   string `json:"version"`
+	Generator string `json:"generator,omitempty"`
+}
+
+type gltfScene struct {
+	Name  string `json:"name,omitempty"`
+	Nodes []int  `json:"nodes,omitempty"`
+}
+
+type gltfNode struct {
+	Name        string    `json:"name,omitempty"`
+	Children    []int     `json:"children,omitempty"`
+	Mesh        *int      `json:"mesh,omitempty"`
+	Matrix      []float32 `json:"matrix,omitempty"`
+	Translation []float32 `json:"translation,omitempty"`
+	Rotation    []float32 `json:"rotation,omitempty"`
+	Scale       []float32 `json:"scale,omitempty"`
+}
+
+type gltfMesh struct {
+	Name       string          `json:"name,omitempty"`
+	Primitives []gltfPrimitive `json:"primitives"`
+}
+
+type gltfPrimitive struct {
+	Attributes map[string]int `json:"attributes"`
+	Indices    *int           `json:"indices,omitempty"`
+	Material   *int           `json:"material,omitempty"`
+	Mode       *int           `json:"mode,omitempty"`
+}
+
+type gltfMaterial struct {
+	Name                 string    `json:"name,omitempty"`
+	PbrMetallicRoughness *gltfPBR  `json:"pbrMetallicRoughness,omitempty"`
+	EmissiveFactor       []float32 `json:"emissiveFactor,omitempty"`
+	AlphaMode            string    `json:"alphaMode,omitempty"`
+}
+
+type gltfPBR struct {
+	BaseColorFactor []float32 `json:"baseColorFactor,omitempty"`
+	MetallicFactor  *float32  `json:"metallicFactor,omitempty"`
+	RoughnessFactor *float32  `json:"roughnessFactor,omitempty"`
+}
+
+type gltfAccessor struct {
+	BufferView    *int            `json:"bufferView,omitempty"`
+	ByteOffset    int             `json:"byteOffset,omitempty"`
+	ComponentType int             `json:"componentType"`
+	Normalized    bool            `json:"normalized,omitempty"`
+	Count         int             `json:"count"`
+	Type          string          `json:"type"`
+	Min           []float32       `json:"min,omitempty"`
+	Max           []float32       `json:"max,omitempty"`
+	Sparse        json.RawMessage `json:"sparse,omitempty"`
+}
+
+type gltfBufferView struct {
+	Buffer     int `json:"buffer"`
+	ByteOffset int `json:"byteOffset,omitempty"`
+	ByteLength int `json:"byteLength"`
+	ByteStride int `json:"byteStride,omitempty"`
+	Target     int `json:"target,omitempty"`
+}
+
+type gltfBuffer struct {
+	URI        string `json:"uri,omitempty"`
+	ByteLength int    `json:"byteLength"`
+}
+
+type gltfAnimation struct {
+	Name     string        `json:"name,omitempty"`
+	Channels []gltfChannel `json:"channels"`
+	Samplers []gltfSampler `json:"samplers"`
+}
+
+type gltfChannel struct {
+	Sampler int        `json:"sampler"`
+	Target  gltfTarget `json:"target"`
+}
+
+type gltfTarget struct {
+	Node *int   `json:"node,omitempty"`
+	Path string `json:"path"`
+}
+
+type gltfSampler struct {
+	Input         int    `json:"input"`
+	Output        int    `json:"output"`
+	Interpolation string `json:"interpolation,omitempty"`
+}
+
+// Constants from the glTF 2.0 specification
+const (
+	gltfMagic        = 0x46546C67 // "glTF"
+	gltfChunkJSON    = 0x4E4F534A // "JSON"
+	gltfChunkBIN     = 0x004E4942 // "BIN\0"
+	gltfByte         = 5120
+	gltfUByte        = 5121
+	gltfShort        = 5122
+	gltfUShort       = 5123
+	gltfUInt         = 5125
+	gltfFloat        = 5126
+	gltfTriangles    = 4
+	gltfStrip        = 5
+	gltfFan          = 6
+	gltfArray        = 34962
+	gltfElementArray = 34963
+	gltfDataPrefix   = "data:application/octet-stream;base64,"
+)
+
+// gltfMaxZeroBytes limits accessors without a buffer view, which take memory without taking room in the file
+const gltfMaxZeroBytes = 1 << 24
+
+// gltfComponents gives the number of components for each accessor type
+var gltfComponents = map[string]int{"SCALAR": 1, "VEC2": 2, "VEC3": 3, "VEC4": 4, "MAT2": 4, "MAT3": 9, "MAT4": 16}
+
+// gltfPaths translates between animation paths and their glTF names
+var gltfPaths = map[string]Path{"translation": Translation, "rotation": Rotation, "scale": Scale}
+
+// LoadGLTF reads a .gltf or .glb file, with the files it refers to next to it
+func LoadGLTF(path string) (*Part, []*Animation, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+	return ReadGLTF(f, DirectoryLibrary(filepath.Dir(path)))
+}
+
+// ReadGLTF reads a glTF 2.0 model, either JSON (.gltf) or binary (.glb)
+// The nodes of the scene become named sub-parts of the returned part, keeping their hierarchy,
+// triangle primitives become meshes with the base color of their material. Buffers are read
+// from the binary chunk, data URIs or through library; external URIs are refused.
+func ReadGLTF(r io.Reader, library Library) (*Part, []*Animation, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// A binary file holds the JSON and the first buffer in chunks
+	var bin []byte
+	if len(data) >= 12 && binary.LittleEndian.Uint32(data) == gltfMagic {
+		if data, bin, err = readGLBChunks(data); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var doc gltfDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, nil, fmt.Errorf("ReadGLTF: %w", err)
+	}
+	if !strings.HasPrefix(doc.Asset.Version, "2.") {
+		return nil, nil, fmt.Errorf("ReadGLTF: expected version 2.x, got %q", doc.Asset.Version)
+	}
+
+	reader := gltfReader{doc: &doc, materials: make(map[int]*Material)}
+	for i, buffer := range doc.Buffers {
+		data, err := readGLTFBuffer(buffer, i, bin, library)
+		if err != nil {
+			return nil, nil, fmt.Errorf("ReadGLTF: buffer %d: %w", i, err)
+		}
+		reader.buffers = append(reader.buffers, data)
+	}
+
+	root, err := reader.readNodes()
+	if err != nil {
+		return nil, nil, fmt.Errorf("ReadGLTF: %w", err)
+	}
+	animations, err := reader.readAnimations()
+	if err != nil {
+		return nil, nil, fmt.Errorf("ReadGLTF: %w", err)
+	}
+	return root, animations, nil
+}
+
+// readGLBChunks splits a binary file into its JSON and binary chunk
+func readGLBChunks(data []byte) ([]byte, []byte, error) {
+	if version := binary.LittleEndian.Uint32(data[4:]); version != 2 {
+		return nil, nil, fmt.Errorf("ReadGLTF: expected version 2, got %d", version)
+	}
+	if length := binary.LittleEndian.Uint32(data[8:]); int(length) > len(data) {
+		return nil, nil, fmt.Errorf("ReadGLTF: file is truncated")
+	}
+
+	var content, bin []byte
+	for offset := 12; offset+8 <= len(data); {
+		length := int(binary.LittleEndian.Uint32(data[offset:]))
+		kind := binary.LittleEndian.Uint32(data[offset+4:])
+		if offset+8+length > len(data) {
+			return nil, nil, fmt.Errorf("ReadGLTF: chunk is truncated")
+		}
+		chunk := data[offset+8 : offset+8+length]
+		switch {
+		case kind == gltfChunkJSON && content == nil:
+			content = chunk
+		case kind == gltfChunkBIN && bin == nil:
+			bin = chunk
+		}
+		offset += 8 + length
+	}
+	if content == nil {
+		return nil, nil, fmt.Errorf("ReadGLTF: no JSON chunk")
+	}
+	return content, bin, nil
+}
+
+// readGLTFBuffer gets the content of a buffer
+func readGLTFBuffer(buffer gltfBuffer, index int, bin []byte, library Library) ([]byte, error) {
+	var data []byte
+	switch {
+	case buffer.URI == "":
+		if index != 0 || bin == nil {
+			return nil, fmt.Errorf("no data")
+		}
+		data = bin
+	case strings.HasPrefix(buffer.URI, "data:"):
+		comma := strings.Index(buffer.URI, ",")
+		if comma < 0 || !strings.HasSuffix(buffer.URI[:comma], ";base64") {
+			return nil, fmt.Errorf("expected a base64 data URI")
+		}
+		decoded, err := base64.StdEncoding.DecodeString(buffer.URI[comma+1:])
+		if err != nil {
+			return nil, err
+		}
+		data = decoded
+	default:
+		name, err := url.PathUnescape(buffer.URI)
+		if err != nil {
+			return nil, err
+		}
+		if strings.Contains(name, ":") || !filepath.IsLocal(filepath.FromSlash(name)) {
+			return nil, fmt.Errorf("only relative local files are supported, got %q", buffer.URI)
+		}
+		if library == nil {
+			return nil, fmt.Errorf("no library to read %q from", name)
+		}
+		f, err := library(name)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		if data, err = io.ReadAll(f); err != nil {
+			return nil, err
+		}
+	}
+
+	if buffer.ByteLength < 0 || len(data) < buffer.ByteLength {
+		return nil, fmt.Errorf("expected %d bytes, got %d", buffer.ByteLength, len(data))
+	}
+	return data[:buffer.ByteLength], nil
+}
+
+// gltfReader translates a glTF document into parts
+type gltfReader struct {
+	doc       *gltfDocument
+	buffers   [][]byte
+	parts     []*Part
+	materials map[int]*Material
+}
+
+// readNodes creates a part for every node and puts them in their hierarchy under a new root
+func (g *gltfReader) readNodes() (*Part, error) {
+	g.parts = make([]*Part, len(g.doc.Nodes))
+	for i := range g.doc.Nodes {
+		part, err := g.readNode(i)
+		if err != nil {
+			return nil, fmt.Errorf("node %d: %w", i, err)
+		}
+		g.parts[i] = part
+	}
+
+	// Hook up the children, every node has one parent at most
+	parents := make([]int, len(g.doc.Nodes))
+	for i := range parents {
+		parents[i] = -1
+	}
+	for i, node := range g.doc.Nodes {
+		for _, c := range node.Children {
+			if c < 0 || c >= len(g.parts) {
+				return nil, fmt.Errorf("node %d: child %d out of range", i, c)
+			}
+			if parents[c] >= 0 || c == i || g.parts[c].contains(g.parts[i]) {
+				return nil, fmt.Errorf("node %d: child %d is already placed", i, c)
+			}
+			parents[c] = i
+			g.parts[i].AddPart(g.nodeName(g.parts[i], c), g.parts[c])
+		}
+	}
+
+	// The scene tells which nodes to show, without a scene all top-level nodes are shown
+	var roots []int
+	switch {
+	case len(g.doc.Scenes) > 0:
+		scene := 0
+		if g.doc.Scene != nil {
+			scene = *g.doc.Scene
+		}
+		if scene < 0 || scene >= len(g.doc.Scenes) {
+			return nil, fmt.Errorf("scene %d out of range", scene)
+		}
+		roots = g.doc.Scenes[scene].Nodes
+	default:
+		for i, parent := range parents {
+			if parent < 0 {
+				roots = append(roots, i)
+			}
+		}
+	}
+
+	root := &Part{}
+	for _, n := range roots {
+		if n < 0 || n >= len(g.parts) || parents[n] >= 0 {
+			return nil, fmt.Errorf("scene node %d is not a root node", n)
+		}
+		root.AddPart(g.nodeName(root, n), g.parts[n])
+	}
+	return root, nil
+}
+
+// nodeName provides a name for a node that is unique within its parent
+func (g *gltfReader) nodeName(parent *Part, node int) string {
+	name := g.doc.Nodes[node].Name
+	if name == "" {
+		name = fmt.Sprintf("node%d", node)
+	}
+	if parent.GetPart(name) != nil {
+		name = fmt.Sprintf("%s#%d", name, node)
+	}
+	return name
+}
+
+// readNode creates the part for a single node, without its children
+func (g *gltfReader) readNode(index int) (*Part, error) {
+	node := g.doc.Nodes[index]
+	part := &Part{}
+
+	switch {
+	case len(node.Matrix) == 16:
+		// Column major, the last column is the translation
+		transform := matrix.NewMatrix([][]float32{
+			node.Matrix[0:4], node.Matrix[4:8], node.Matrix[8:12], node.Matrix[12:16],
+		}).Transpose()
+		if err := part.TrySetTransform(matrix.Linear(transform), vector.NewVector(node.Matrix[12:15])); err != nil {
+			return nil, err
+		}
+	case len(node.Matrix) != 0:
+		return nil, fmt.Errorf("matrix needs 16 values, got %d", len(node.Matrix))
+	default:
+		if len(node.Translation) == 3 {
+			part.SetPosition(vector.NewVector(node.Translation))
+		}
+		if len(node.Rotation) == 4 {
+			if err := part.TrySetOrientation(quaternion.FromVector(vector.NewVector(node.Rotation))); err != nil {
+				return nil, err
+			}
+		}
+		if len(node.Scale) == 3 {
+			part.SetScale(vector.NewVector(node.Scale))
+		}
+	}
+
+	if node.Mesh != nil {
+		if *node.Mesh < 0 || *node.Mesh >= len(g.doc.Meshes) {
+			return nil, fmt.Errorf("mesh %d out of range", *node.Mesh)
+		}
+		for p, primitive := range g.doc.Meshes[*node.Mesh].Primitives {
+			if err := g.readPrimitive(primitive, part.GetGeometry()); err != nil {
+				return nil, fmt.Errorf("mesh %d primitive %d: %w", *node.Mesh, p, err)
+			}
+		}
+	}
+	return part, nil
+}
+
+// readPrimitive adds the vertices and triangles of a primitive to geometry
+func (g *gltfReader) readPrimitive(primitive gltfPrimitive, geometry *Geometry) error {
+	mode := gltfTriangles
+	if primitive.Mode != nil {
+		mode = *primitive.Mode
+	}
+	if mode != gltfTriangles && mode != gltfStrip && mode != gltfFan {
+		return fmt.Errorf("only triangles are supported, got mode %d", mode)
+	}
+
+	position, ok := primitive.Attributes["POSITION"]
+	if !ok {
+		return fmt.Errorf("no positions")
+	}
+	positions, err := g.readFloats(position, "VEC3")
+	if err != nil {
+		return err
+	}
+	normals, uvs, colors, err := g.readAttributes(primitive, len(positions))
+	if err != nil {
+		return err
+	}
+
+	// Without indices the vertices are used in order
+	var indices []int
+	if primitive.Indices != nil {
+		if indices, err = g.readIndices(*primitive.Indices); err != nil {
+			return err
+		}
+	} else {
+		indices = make([]int, len(positions))
+		for i := range indices {
+			indices[i] = i
+		}
+	}
+	for _, i := range indices {
+		if i >= len(positions) {
+			return fmt.Errorf("index %d out of range, have %d", i, len(positions))
+		}
+	}
+
+	var material *Material
+	if primitive.Material != nil {
+		if material, err = g.readMaterial(*primitive.Material); err != nil {
+			return err
+		}
+	}
+
+	base := geometry.VertexCount()
+	for i, position := range positions {
+		index := geometry.AddVertex(vector.NewVector(position))
+		if normals != nil {
+			geometry.SetNormal(index, vector.NewVector(normals[i]))
+		}
+		if uvs != nil {
+			// glTF has v going down the texture
+			geometry.SetUV(index, vector.NewVector([]float32{uvs[i][0], 1.0 - uvs[i][1]}))
+		}
+		if colors != nil {
+			alpha := float32(1.0)
+			if len(colors[i]) == 4 {
+				alpha = colors[i][3]
+			}
+			geometry.SetColor(index, toNRGBA(colors[i][0:3], alpha))
+		}
+	}
+	add := func(a int, b int, c int) {
+		geometry.AddTriangle(base+a, base+b, base+c, material)
+	}
+	switch mode {
+	case gltfTriangles:
+		for i := 0; i+2 < len(indices); i += 3 {
+			add(indices[i], indices[i+1], indices[i+2])
+		}
+	case gltfStrip:
+		// Every other triangle is flipped to keep the winding
+		for i := 0; i+2 < len(indices); i++ {
+			if i%2 == 0 {
+				add(indices[i], indices[i+1], indices[i+2])
+			} else {
+				add(indices[i+1], indices[i], indices[i+2])
+			}
+		}
+	case gltfFan:
+		for i := 1; i+1 < len(indices); i++ {
+			add(indices[0], indices[i], indices[i+1])
+		}
+	}
+	return nil
+}
+
+// readAttributes reads the optional normals, texture coordinates and colors of the vertices of a primitive
+func (g *gltfReader) readAttributes(primitive gltfPrimitive, count int) ([][]float32, [][]float32, [][]float32, error) {
+	var normals, uvs, colors [][]float32
+	var err error
+	if index, ok := primitive.Attributes["NORMAL"]; ok {
+		if normals, err = g.readFloats(index, "VEC3"); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	if index, ok := primitive.Attributes["TEXCOORD_0"]; ok {
+		if uvs, err = g.readFloats(index, "VEC2"); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	if index, ok := primitive.Attributes["COLOR_0"]; ok {
+		kind := "VEC4"
+		if index >= 0 && index < len(g.doc.Accessors) && g.doc.Accessors[index].Type == "VEC3" {
+			kind = "VEC3"
+		}
+		if colors, err = g.readFloats(index, kind); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	for _, attribute := range [][][]float32{normals, uvs, colors} {
+		if attribute != nil && len(attribute) != count {
+			return nil, nil, nil, fmt.Errorf("expected %d values for every attribute, got %d", count, len(attribute))
+		}
+	}
+	return normals, uvs, colors, nil
+}
+
+// readMaterial translates the base color, opacity and emission of a material
+func (g *gltfReader) readMaterial(index int) (*Material, error) {
+	if material, ok := g.materials[index]; ok {
+		return material, nil
+	}
+	if index < 0 || index >= len(g.doc.Materials) {
+		return nil, fmt.Errorf("material %d out of range", index)
+	}
+	source := g.doc.Materials[index]
+
+	material := DefaultMaterial()
+	if source.PbrMetallicRoughness != nil && len(source.PbrMetallicRoughness.BaseColorFactor) == 4 {
+		base := source.PbrMetallicRoughness.BaseColorFactor
+		material.SetDiffuse(toNRGBA(base[0:3], 1.0))
+		if source.AlphaMode == "BLEND" {
+			material.SetOpacity(clamp(base[3]))
+		}
+	}
+	if len(source.EmissiveFactor) == 3 {
+		material.SetEmissive(toNRGBA(source.EmissiveFactor, 1.0))
+	}
+
+	g.materials[index] = material
+	return material, nil
+}
+
+// readAnimations translates the translation, rotation and scale channels of all animations
+// Cubic spline key frames lose their tangents and are interpolated linearly.
+func (g *gltfReader) readAnimations() ([]*Animation, error) {
+	var animations []*Animation
+	for a, source := range g.doc.Animations {
+		animation := NewAnimation(source.Name)
+		for c, ch := range source.Channels {
+			path, ok := gltfPaths[ch.Target.Path]
+			if !ok || ch.Target.Node == nil {
+				// Morph target weights aren't supported
+				continue
+			}
+			if *ch.Target.Node < 0 || *ch.Target.Node >= len(g.parts) {
+				return nil, fmt.Errorf("animation %d channel %d: node %d out of range", a, c, *ch.Target.Node)
+			}
+			if ch.Sampler < 0 || ch.Sampler >= len(source.Samplers) {
+				return nil, fmt.Errorf("animation %d channel %d: sampler %d out of range", a, c, ch.Sampler)
+			}
+			sampler := source.Samplers[ch.Sampler]
+
+			times, err := g.readFloats(sampler.Input, "SCALAR")
+			if err != nil {
+				return nil, fmt.Errorf("animation %d channel %d: %w", a, c, err)
+			}
+			kind := "VEC3"
+			if path == Rotation {
+				kind = "VEC4"
+			}
+			output, err := g.readFloats(sampler.Output, kind)
+			if err != nil {
+				return nil, fmt.Errorf("animation %d channel %d: %w", a, c, err)
+			}
+
+			// Cubic splines have an in-tangent, a value and an out-tangent for every key frame
+			stride, offset := 1, 0
+			if sampler.Interpolation == "CUBICSPLINE" {
+				stride, offset = 3, 1
+			}
+			if len(output) != len(times)*stride || len(times) == 0 {
+				return nil, fmt.Errorf("animation %d channel %d: %d times don't match %d values", a, c, len(times), len(output))
+			}
+			keys := make([]float32, len(times))
+			values := make([]vector.Vector, len(times))
+			for i := range times {
+				keys[i] = times[i][0]
+				values[i] = vector.NewVector(output[i*stride+offset])
+				if i > 0 && keys[i] < keys[i-1] {
+					return nil, fmt.Errorf("animation %d channel %d: times must be increasing", a, c)
+				}
+				if path == Rotation {
+					if _, err := quaternion.FromVector(values[i]).TryNormalize(); err != nil {
+						return nil, fmt.Errorf("animation %d channel %d: key %d: %w", a, c, i, err)
+					}
+				}
+			}
+			animation.AddChannel(g.parts[*ch.Target.Node], path, keys, values, sampler.Interpolation == "STEP")
+		}
+		animations = append(animations, animation)
+	}
+	return animations, nil
+}
+
+// readIndices reads an accessor of unsigned integers
+func (g *gltfReader) readIndices(index int) ([]int, error) {
+	accessor, data, stride, err := g.accessor(index, "SCALAR")
+	if err != nil {
+		return nil, err
+	}
+
+	indices := make([]int, accessor.Count)
+	for i := range indices {
+		element := data[i*stride:]
+		switch accessor.ComponentType {
+		case gltfUByte:
+			indices[i] = int(element[0])
+		case gltfUShort:
+			indices[i] = int(binary.LittleEndian.Uint16(element))
+		case gltfUInt:
+			indices[i] = int(binary.LittleEndian.Uint32(element))
+		default:
+			return nil, fmt.Errorf("accessor %d: indices can't have component type %d", index, accessor.ComponentType)
+		}
+	}
+	return indices, nil
+}
+
+// readFloats reads an accessor of floats, or normalized integers, as one slice per element
+func (g *gltfReader) readFloats(index int, kind string) ([][]float32, error) {
+	accessor, data, stride, err := g.accessor(index, kind)
+	if err != nil {
+		return nil, err
+	}
+	if accessor.ComponentType != gltfFloat && !accessor.Normalized {
+		return nil, fmt.Errorf("accessor %d: expected floats, got component type %d", index, accessor.ComponentType)
+	}
+
+	components := gltfComponents[kind]
+	size := componentSize(accessor.ComponentType)
+	values := make([][]float32, accessor.Count)
+	for i := range values {
+		values[i] = make([]float32, components)
+		for c := range values[i] {
+			element := data[i*stride+c*size:]
+			switch accessor.ComponentType {
+			case gltfFloat:
+				values[i][c] = math.Float32frombits(binary.LittleEndian.Uint32(element))
+			case gltfByte:
+				values[i][c] = float32(math.Max(float64(int8(element[0]))/127.0, -1.0))
+			case gltfUByte:
+				values[i][c] = float32(element[0]) / 255.0
+			case gltfShort:
+				values[i][c] = float32(math.Max(float64(int16(binary.LittleEndian.Uint16(element)))/32767.0, -1.0))
+			case gltfUShort:
+				values[i][c] = float32(binary.LittleEndian.Uint16(element)) / 65535.0
+			default:
+				return nil, fmt.Errorf("accessor %d: unsupported component type %d", index, accessor.ComponentType)
+			}
+		}
+	}
+	return values, nil
+}
+
+// accessor checks an accessor and provides the data it refers to with the distance between elements
+func (g *gltfReader) accessor(index int, kind string) (gltfAccessor, []byte, int, error) {
+	if index < 0 || index >= len(g.doc.Accessors) {
+		return gltfAccessor{}, nil, 0, fmt.Errorf("accessor %d out of range", index)
+	}
+	accessor := g.doc.Accessors[index]
+	if accessor.Type != kind {
+		return accessor, nil, 0, fmt.Errorf("accessor %d: expected %s, got %s", index, kind, accessor.Type)
+	}
+	if len(accessor.Sparse) > 0 {
+		return accessor, nil, 0, fmt.Errorf("accessor %d: sparse accessors aren't supported", index)
+	}
+	size := componentSize(accessor.ComponentType)
+	if size == 0 {
+		return accessor, nil, 0, fmt.Errorf("accessor %d: unknown component type %d", index, accessor.ComponentType)
+	}
+	element := size * gltfComponents[kind]
+	if accessor.Count < 0 || accessor.ByteOffset < 0 {
+		return accessor, nil, 0, fmt.Errorf("accessor %d: count and offset can't be negative", index)
+	}
+
+	// Without a buffer view everything is zero, the count is all there is to go by
+	if accessor.BufferView == nil {
+		if accessor.Count > gltfMaxZeroBytes/element {
+			return accessor, nil, 0, fmt.Errorf("accessor %d: %d elements without a buffer view are too many", index, accessor.Count)
+		}
+		return accessor, make([]byte, accessor.Count*element), element, nil
+	}
+	if *accessor.BufferView < 0 || *accessor.BufferView >= len(g.doc.BufferViews) {
+		return accessor, nil, 0, fmt.Errorf("accessor %d: buffer view %d out of range", index, *accessor.BufferView)
+	}
+	view := g.doc.BufferViews[*accessor.BufferView]
+	if view.Buffer < 0 || view.Buffer >= len(g.buffers) {
+		return accessor, nil, 0, fmt.Errorf("accessor %d: buffer %d out of range", index, view.Buffer)
+	}
+	buffer := g.buffers[view.Buffer]
+	if view.ByteOffset < 0 || view.ByteLength < 0 || view.ByteOffset > len(buffer) || view.ByteLength > len(buffer)-view.ByteOffset {
+		return accessor, nil, 0, fmt.Errorf("accessor %d: buffer view %d doesn't fit its buffer", index, *accessor.BufferView)
+	}
+	data := buffer[view.ByteOffset : view.ByteOffset+view.ByteLength]
+
+	stride := element
+	if view.ByteStride > 0 {
+		stride = view.ByteStride
+	}
+
+	// Dividing the room left by the stride can't overflow, multiplying the count by it can
+	if accessor.Count > 0 && (accessor.ByteOffset > len(data)-element ||
+		accessor.Count-1 > (len(data)-element-accessor.ByteOffset)/stride) {
+		return accessor, nil, 0, fmt.Errorf("accessor %d doesn't fit its buffer view", index)
+	}
+	return accessor, data[accessor.ByteOffset:], stride, nil
+}
+
+// componentSize gives the number of bytes of a component type, 0 if it is unknown
+func componentSize(componentType int) int {
+	switch componentType {
+	case gltfByte, gltfUByte:
+		return 1
+	case gltfShort, gltfUShort:
+		return 2
+	case gltfUInt, gltfFloat:
+		return 4
+	}
+	return 0
+}
+
+// toNRGBA translates r, g, b and alpha factors into a color
+func toNRGBA(rgb []float32, alpha float32) color.Color {
+	return color.NRGBA{
+		uint8(clamp(rgb[0])*255.0 + 0.5),
+		uint8(clamp(rgb[1])*255.0 + 0.5),
+		uint8(clamp(rgb[2])*255.0 + 0.5),
+		uint8(clamp(alpha)*255.0 + 0.5),
+	}
+}
+
+// WriteGLTF writes a part with its sub-parts and animations as glTF 2.0 JSON
+// The binary data is embedded as a data URI, so the result is a single file.
+func WriteGLTF(w io.Writer, part *Part, animations []*Animation) error {
+	g, err := newGLTFWriter(part, animations)
+	if err != nil {
+		return err
+	}
+	if len(g.bin) > 0 {
+		g.doc.Buffers = []gltfBuffer{{URI: gltfDataPrefix + base64.StdEncoding.EncodeToString(g.bin), ByteLength: len(g.bin)}}
+	}
+
+	content, err := json.MarshalIndent(g.doc, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(content)
+	return err
+}
+
+// WriteGLB writes a part with its sub-parts and animations as binary glTF 2.0
+func WriteGLB(w io.Writer, part *Part, animations []*Animation) error {
+	g, err := newGLTFWriter(part, animations)
+	if err != nil {
+		return err
+	}
+	if len(g.bin) > 0 {
+		g.doc.Buffers = []gltfBuffer{{ByteLength: len(g.bin)}}
+	}
+
+	content, err := json.Marshal(g.doc)
+	if err != nil {
+		return err
+	}
+
+	// Chunks are padded to 4 bytes, JSON with spaces and binary with zeros
+	for len(content)%4 != 0 {
+		content = append(content, ' ')
+	}
+	bin := g.bin
+	for len(bin)%4 != 0 {
+		bin = append(bin, 0)
+	}
+	length := 12 + 8 + len(content)
+	if len(bin) > 0 {
+		length += 8 + len(bin)
+	}
+
+	var b bytes.Buffer
+	binary.Write(&b, binary.LittleEndian, []uint32{gltfMagic, 2, uint32(length), uint32(len(content)), gltfChunkJSON})
+	b.Write(content)
+	if len(bin) > 0 {
+		binary.Write(&b, binary.LittleEndian, []uint32{uint32(len(bin)), gltfChunkBIN})
+		b.Write(bin)
+	}
+	_, err = w.Write(b.Bytes())
+	return err
+}
+
+// gltfWriter collects a glTF document with its binary data
+type gltfWriter struct {
+	doc       gltfDocument
+	bin       []byte
+	nodes     map[*Part]int
+	materials map[*Material]int
+}
+
+// newGLTFWriter translates a part and animations into a glTF document
+// A part without meshes or transformation of its own, like the one ReadGLTF provides, doesn't
+// get a node: its sub-parts go directly into the scene.
+func newGLTFWriter(part *Part, animations []*Animation) (*gltfWriter, error) {
+	g := &gltfWriter{
+		doc:       gltfDocument{Asset: gltfAsset{Version: "2.0", Generator: "drawing-experiment"}},
+		nodes:     make(map[*Part]int),
+		materials: make(map[*Material]int),
+	}
+
+	var roots []int
+	if (part.geometry == nil || part.geometry.TriangleCount() == 0) && part.isIdentity() && part.material == nil {
+		for _, c := range part.children {
+			roots = append(roots, g.addNode(c.name, c.part, nil))
+		}
+	} else {
+		roots = []int{g.addNode("root", part, nil)}
+	}
+	scene := 0
+	g.doc.Scene = &scene
+	g.doc.Scenes = []gltfScene{{Nodes: roots}}
+
+	for _, animation := range animations {
+		if err := g.addAnimation(animation); err != nil {
+			return nil, err
+		}
+	}
+	return g, nil
+}
+
+// addNode adds a node for a part and its sub-parts, the part inherits material from its parent
+func (g *gltfWriter) addNode(name string, part *Part, material *Material) int {
+	index := len(g.doc.Nodes)
+	g.doc.Nodes = append(g.doc.Nodes, gltfNode{Name: name})
+	g.nodes[part] = index
+
+	node := gltfNode{Name: name}
+	transform, position := part.transform()
+	if part.hasShear() {
+		// Only a matrix can hold a shear, column major: the rows of the transpose
+		columns := matrix.Affine(transform, position).Transpose().(*matrix.Mat4)
+		node.Matrix = append([]float32{}, columns[:]...)
+	} else {
+		node.Translation = components(position)
+		node.Rotation = components(part.GetOrientation().Vector())
+		node.Scale = []float32{part.scaling.Get(0, 0).(float32), part.scaling.Get(1, 1).(float32), part.scaling.Get(2, 2).(float32)}
+	}
+
+	if part.material != nil {
+		material = part.material
+	}
+	if part.geometry != nil && part.geometry.TriangleCount() > 0 {
+		mesh := g.addMesh(name, part.geometry, material)
+		node.Mesh = &mesh
+	}
+	for _, c := range part.children {
+		node.Children = append(node.Children, g.addNode(c.name, c.part, material))
+	}
+
+	g.doc.Nodes[index] = node
+	return index
+}
+
+// addMesh adds the geometry of a part as an indexed mesh with a primitive for every material
+func (g *gltfWriter) addMesh(name string, geometry *Geometry, material *Material) int {
+	var order []*Material
+	groups := make(map[*Material][]int)
+	for i, t := range geometry.triangles {
+		m := t.material
+		if m == nil {
+			m = material
+		}
+		if _, ok := groups[m]; !ok {
+			order = append(order, m)
+		}
+		groups[m] = append(groups[m], i)
+	}
+
+	result := gltfMesh{Name: name}
+	for _, m := range order {
+		// Every primitive takes the vertices it uses, in the order it uses them
+		var used []int
+		var indices []uint32
+		local := make(map[int]uint32)
+		for _, i := range groups[m] {
+			for _, v := range geometry.triangles[i].indices {
+				index, ok := local[v]
+				if !ok {
+					index = uint32(len(used))
+					local[v] = index
+					used = append(used, v)
+				}
+				indices = append(indices, index)
+			}
+		}
+
+		// Attributes only go along when every vertex has them
+		hasNormals, hasUVs, hasColors := true, true, true
+		for _, v := range used {
+			hasNormals = hasNormals && geometry.GetNormal(v) != nil
+			hasUVs = hasUVs && geometry.GetUV(v) != nil
+			hasColors = hasColors && geometry.GetColor(v) != nil
+		}
+		var positions, normals, uvs, colors []float32
+		for _, v := range used {
+			positions = append(positions, components(geometry.vertices[v])...)
+			if hasNormals {
+				normals = append(normals, components(geometry.GetNormal(v))...)
+			}
+			if hasUVs {
+				uv := geometry.GetUV(v)
+				uvs = append(uvs, uv.Get(0).(float32), 1.0-uv.Get(1).(float32))
+			}
+			if hasColors {
+				c := color.NRGBAModel.Convert(geometry.GetColor(v)).(color.NRGBA)
+				colors = append(colors, float32(c.R)/255.0, float32(c.G)/255.0, float32(c.B)/255.0, float32(c.A)/255.0)
+			}
+		}
+
+		indexAccessor := g.addIndices(indices)
+		primitive := gltfPrimitive{
+			Attributes: map[string]int{"POSITION": g.addAccessor(positions, "VEC3", gltfArray, true)},
+			Indices:    &indexAccessor,
+		}
+		if normals != nil {
+			primitive.Attributes["NORMAL"] = g.addAccessor(normals, "VEC3", gltfArray, false)
+		}
+		if uvs != nil {
+			primitive.Attributes["TEXCOORD_0"] = g.addAccessor(uvs, "VEC2", gltfArray, false)
+		}
+		if colors != nil {
+			primitive.Attributes["COLOR_0"] = g.addAccessor(colors, "VEC4", gltfArray, false)
+		}
+		if m != nil {
+			index := g.addMaterial(m)
+			primitive.Material = &index
+		}
+		result.Primitives = append(result.Primitives, primitive)
+	}
+	g.doc.Meshes = append(g.doc.Meshes, result)
+	return len(g.doc.Meshes) - 1
+}
+
+// addMaterial adds a material once, as a rough non-metallic base color
+func (g *gltfWriter) addMaterial(material *Material) int {
+	if index, ok := g.materials[material]; ok {
+		return index
+	}
+
+	metallic, roughness := float32(0.0), float32(1.0)
+	diffuse := color.NRGBAModel.Convert(material.diffuse).(color.NRGBA)
+	emissive := color.NRGBAModel.Convert(material.emissive).(color.NRGBA)
+	result := gltfMaterial{
+		PbrMetallicRoughness: &gltfPBR{
+			BaseColorFactor: []float32{float32(diffuse.R) / 255.0, float32(diffuse.G) / 255.0, float32(diffuse.B) / 255.0, material.opacity},
+			MetallicFactor:  &metallic,
+			RoughnessFactor: &roughness,
+		},
+		EmissiveFactor: []float32{float32(emissive.R) / 255.0, float32(emissive.G) / 255.0, float32(emissive.B) / 255.0},
+	}
+	if material.opacity < 1.0 {
+		result.AlphaMode = "BLEND"
+	}
+
+	g.doc.Materials = append(g.doc.Materials, result)
+	g.materials[material] = len(g.doc.Materials) - 1
+	return len(g.doc.Materials) - 1
+}
+
+// addAnimation adds the channels of an animation, the parts must have been added as nodes
+func (g *gltfWriter) addAnimation(animation *Animation) error {
+	result := gltfAnimation{Name: animation.name}
+	for _, c := range animation.channels {
+		node, ok := g.nodes[c.part]
+		if !ok {
+			return fmt.Errorf("WriteGLTF: animation %q moves a part that isn't in the model", animation.name)
+		}
+		if c.part.hasShear() {
+			return fmt.Errorf("WriteGLTF: animation %q moves a sheared part", animation.name)
+		}
+
+		kind := "VEC3"
+		if c.path == Rotation {
+			kind = "VEC4"
+		}
+		var values []float32
+		for _, v := range c.values {
+			values = append(values, components(v)...)
+		}
+		interpolation := "LINEAR"
+		if c.step {
+			interpolation = "STEP"
+		}
+
+		for name, path := range gltfPaths {
+			if path != c.path {
+				continue
+			}
+			result.Samplers = append(result.Samplers, gltfSampler{
+				Input:         g.addAccessor(c.times, "SCALAR", 0, true),
+				Output:        g.addAccessor(values, kind, 0, false),
+				Interpolation: interpolation,
+			})
+			result.Channels = append(result.Channels, gltfChannel{
+				Sampler: len(result.Samplers) - 1,
+				Target:  gltfTarget{Node: &node, Path: name},
+			})
+		}
+	}
+
+	g.doc.Animations = append(g.doc.Animations, result)
+	return nil
+}
+
+// addAccessor adds floats to the binary data, with a buffer view of their own
+func (g *gltfWriter) addAccessor(values []float32, kind string, target int, bounds bool) int {
+	components := gltfComponents[kind]
+	view := gltfBufferView{Buffer: 0, ByteOffset: len(g.bin), ByteLength: len(values) * 4, Target: target}
+	for _, v := range values {
+		g.bin = binary.LittleEndian.AppendUint32(g.bin, math.Float32bits(v))
+	}
+	g.doc.BufferViews = append(g.doc.BufferViews, view)
+
+	index := len(g.doc.BufferViews) - 1
+	accessor := gltfAccessor{BufferView: &index, ComponentType: gltfFloat, Count: len(values) / components, Type: kind}
+	if bounds && len(values) > 0 {
+		accessor.Min = append([]float32{}, values[:components]...)
+		accessor.Max = append([]float32{}, values[:components]...)
+		for i, v := range values {
+			c := i % components
+			accessor.Min[c] = float32(math.Min(float64(accessor.Min[c]), float64(v)))
+			accessor.Max[c] = float32(math.Max(float64(accessor.Max[c]), float64(v)))
+		}
+	}
+	g.doc.Accessors = append(g.doc.Accessors, accessor)
+	return len(g.doc.Accessors) - 1
+}
+
+// addIndices adds unsigned integer vertex indices to the binary data, with a buffer view of their own
+func (g *gltfWriter) addIndices(indices []uint32) int {
+	view := gltfBufferView{Buffer: 0, ByteOffset: len(g.bin), ByteLength: len(indices) * 4, Target: gltfElementArray}
+	for _, i := range indices {
+		g.bin = binary.LittleEndian.AppendUint32(g.bin, i)
+	}
+	g.doc.BufferViews = append(g.doc.BufferViews, view)
+
+	index := len(g.doc.BufferViews) - 1
+	g.doc.Accessors = append(g.doc.Accessors, gltfAccessor{BufferView: &index, ComponentType: gltfUInt, Count: len(indices), Type: "SCALAR"})
+	return len(g.doc.Accessors) - 1
+}
+
+// isIdentity checks if the part leaves the coordinates of its meshes and sub-parts alone
+func (p *Part) isIdentity() bool {
+	transform, position := p.transform()
+	return transform.Equal(matrix.UnitMatrix(3, 3, transform.Kind())) && position.Abs() == 0.0
+}
+
+// hasShear checks if the part is sheared
+func (p *Part) hasShear() bool {
+	p.transform()
+	return !p.shearing.Equal(matrix.UnitMatrix(3, 3, p.shearing.Kind()))
+}
+
+// components pulls the values out of a Float32 vector
+func components(v vector.Vector) []float32 {
+	result := make([]float32, v.Len())
+	for i := range result {
+		result[i] = v.Get(i).(float32)
+	}
+	return result
+}
@@ -0,0 +1,160 @@
+package model
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image/color"
+	"io/fs"
+	"strings"
+	"testing"
+
+	"../number/quaternion"
+	"../number/vector"
+)
+
+// testGLTFModel builds a red body with a wheel that turns a quarter arround z in 2 seconds
+func testGLTFModel() (*Part, *Animation) {
+	body := NewBox(4.0, 2.0, 1.0)
+	red := NewMaterial(color.NRGBA{0xff, 0x00, 0x00, 0xff})
+	red.SetOpacity(0.5)
+	body.SetMaterial(red)
+
+	wheel := NewBox(1.0, 1.0, 1.0)
+	wheel.SetPosition(vector.NewVector([]float32{2.0, 0.0, 0.0}))
+	body.AddPart("wheel", &wheel.Part)
+
+	// A flag with all vertex attributes
+	flag := &Part{}
+	normal := vector.NewVector([]float32{0.0, -1.0, 0.0})
+	flag.GetGeometry().AddMesh(NewMesh([]vector.Vector{
+		vector.NewVector([]float32{0.0, 0.0, 0.0}), vector.NewVector([]float32{1.0, 0.0, 0.0}), vector.NewVector([]float32{0.0, 0.0, 1.0}),
+	}).SetNormals([]vector.Vector{normal, normal, normal}).SetUVs([]vector.Vector{
+		vector.NewVector([]float32{0.0, 0.0}), vector.NewVector([]float32{1.0, 0.0}), vector.NewVector([]float32{0.0, 1.0}),
+	}).SetColors([]color.Color{color.White, color.Black, color.NRGBA{0x00, 0x00, 0xff, 0xff}}))
+
+	root := &Part{}
+	root.AddPart("body", &body.Part)
+	root.AddPart("flag", flag)
+
+	animation := NewAnimation("turn")
+	animation.AddChannel(&wheel.Part, Rotation, []float32{0.0, 2.0}, []vector.Vector{
+		vector.NewVector([]float32{0.0, 0.0, 0.0, 1.0}),
+		vector.NewVector([]float32{0.0, 0.0, 0.7071068, 0.7071068}),
+	}, false)
+	return root, animation
+}
+
+func Test_GLTFRoundTrip(t *testing.T) {
+	for _, binary := range []bool{false, true} {
+		root, animation := testGLTFModel()
+		var b bytes.Buffer
+		write := WriteGLTF
+		if binary {
+			write = WriteGLB
+		}
+		if err := write(&b, root, []*Animation{animation}); err != nil {
+			t.Fatalf("Write (binary %v): %v", binary, err)
+		}
+
+		part, animations, err := ReadGLTF(&b, nil)
+		if err != nil {
+			t.Fatalf("ReadGLTF (binary %v): %v", binary, err)
+		}
+		body := part.GetPart("body")
+		if body == nil || body.GetPart("wheel") == nil {
+			t.Fatalf("Expected body with wheel, got %v", part.GetPartNames())
+		}
+
+		// The meshes end up in the same place, with the material of the body
+		expected, got := root.GetMeshes(), part.GetMeshes()
+		if len(got) != len(expected) {
+			t.Fatalf("Expected %d meshes, got %d", len(expected), len(got))
+		}
+		for i := range got {
+			for v := 0; v < 3; v++ {
+				if !near(got[i].GetVertex(v), expected[i].GetVertex(v)) {
+					t.Fatalf("Mesh %d: expected %v, got %v", i, expected[i], got[i])
+				}
+			}
+		}
+		flag := part.GetPart("flag").GetGeometry().Meshes()[0]
+		if flag.GetNormal(0) == nil || !near(flag.GetNormal(0), vector.NewVector([]float32{0.0, -1.0, 0.0})) ||
+			flag.GetUV(2) == nil || !near(flag.GetUV(2), vector.NewVector([]float32{0.0, 1.0})) ||
+			flag.GetColor(2) != (color.NRGBA{0x00, 0x00, 0xff, 0xff}) {
+			t.Errorf("Expected the flag attributes to survive, got %v %v %v", flag.GetNormal(0), flag.GetUV(2), flag.GetColor(2))
+		}
+		material := got[len(got)-2].GetMaterial()
+		if material == nil || material.GetDiffuse() != (color.NRGBA{0xff, 0x00, 0x00, 0xff}) || material.GetOpacity() != 0.5 {
+			t.Errorf("Expected translucent red, got %v", material)
+		}
+
+		// Halfway the wheel has turned 45 degrees
+		if len(animations) != 1 || animations[0].GetName() != "turn" || animations[0].Duration() != 2.0 {
+			t.Fatalf("Expected the turn animation, got %v", animations)
+		}
+		animations[0].Apply(1.0)
+		rotation := body.GetPart("wheel").GetOrientation().Vector()
+		if !near(rotation, vector.NewVector([]float32{0.0, 0.0, 0.3826834, 0.9238795})) {
+			t.Errorf("Expected 45 degrees arround z, got %v", rotation)
+		}
+	}
+}
+
+func Test_ReadGLTFErrors(t *testing.T) {
+	// A zero quaternion is no rotation at all
+	rotation := `{"asset": {"version": "2.0"}, "nodes": [{"rotation": [0, 0, 0, 0]}]}`
+	// Accessors without a buffer view are zero, also when the key frames are rotations
+	keys := `{"asset": {"version": "2.0"}, "nodes": [{}], "animations": [{"channels": [{"sampler": 0, "target": {"node": 0, "path": "rotation"}}],
+		"samplers": [{"input": 0, "output": 1}]}], "accessors": [{"componentType": 5126, "count": 1, "type": "SCALAR"},
+		{"componentType": 5126, "count": 1, "type": "VEC4"}]}`
+	// A zero scale has no rotation to decompose
+	singular := `{"asset": {"version": "2.0"}, "nodes": [{"matrix": [0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1]}]}`
+	tests := []string{
+		`{"asset": {"version": "1.0"}}`,
+		`{"asset": {"version": "2.0"}, "buffers": [{"uri": "https://example.com/data.bin", "byteLength": 4}]}`,
+		`{"asset": {"version": "2.0"}, "nodes": [{"children": [1]}, {"children": [0]}]}`,
+		`{"asset": {"version": "2.0"}, "nodes": [{"mesh": 0}], "meshes": [{"primitives": [{"attributes": {"POSITION": 0}}]}]}`,
+		`{"asset": {"version": "2.0"}, "nodes": [{"mesh": 0}], "meshes": [{"primitives": [{"attributes": {"POSITION": 0}}]}],
+		  "accessors": [{"bufferView": 0, "componentType": 5126, "count": 3, "type": "VEC3"}],
+		  "bufferViews": [{"buffer": 0, "byteLength": 12}], "buffers": [{"uri": "data:application/octet-stream;base64,AAAAAAAAAAAAAAAA", "byteLength": 12}]}`,
+		singular,
+		`{"asset": {"version": "2.0"}, "buffers": [{"uri": "../../../etc/passwd", "byteLength": 4}]}`,
+		`{"asset": {"version": "2.0"}, "buffers": [{"uri": "data/%2E%2E/%2E%2E/secret.bin", "byteLength": 4}]}`,
+		`{"asset": {"version": "2.0"}, "buffers": [{"uri": "data:application/octet-stream;base64,AAAAAA==", "byteLength": -1}]}`,
+		zeroPositions(-3),
+		zeroPositions(4611686018427387904),
+		`{"asset": {"version": "2.0"}, "nodes": [{"mesh": 0}], "meshes": [{"primitives": [{"attributes": {"POSITION": 0}}]}],
+		  "accessors": [{"bufferView": 0, "componentType": 5126, "count": 768614336404564651, "type": "VEC3"}],
+		  "bufferViews": [{"buffer": 0, "byteLength": 12, "byteStride": 12}], "buffers": [{"uri": "data:application/octet-stream;base64,AAAAAAAAAAAAAAAA", "byteLength": 12}]}`,
+		rotation,
+	}
+	library := testLibrary(map[string]string{"../../../etc/passwd": "root", "../secret.bin": "data"})
+	for i, test := range tests {
+		if _, _, err := ReadGLTF(strings.NewReader(test), library); err == nil {
+			t.Errorf("Test %d: expected an error", i)
+		}
+	}
+
+	// The reasons survive the wrapping
+	reasons := []struct {
+		document string
+		expected error
+	}{
+		{`{"asset": {"version": "2.0"}, "buffers": [{"uri": "missing.bin", "byteLength": 4}]}`, fs.ErrNotExist},
+		{singular, ErrSingular},
+		{rotation, quaternion.ErrZero},
+		{keys, quaternion.ErrZero},
+	}
+	for i, reason := range reasons {
+		if _, _, err := ReadGLTF(strings.NewReader(reason.document), library); !errors.Is(err, reason.expected) {
+			t.Errorf("Reason %d: expected %v, got %v", i, reason.expected, err)
+		}
+	}
+}
+
+// zeroPositions provides a document with a triangle of count positions without a buffer view
+func zeroPositions(count int) string {
+	return fmt.Sprintf(`{"asset": {"version": "2.0"}, "nodes": [{"mesh": 0}], "meshes": [{"primitives": [{"attributes": {"POSITION": 0}}]}],
+		"accessors": [{"componentType": 5126, "count": %d, "type": "VEC3"}]}`, count)
+}
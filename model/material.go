@@ -0,0 +1,100 @@
+package model
+
+import (
+	"image"
+	"image/color"
+	"log"
+)
+
+// Material describes how the surface of a Mesh looks and reacts to light
+type Material struct {
+	diffuse   color.Color // color under direct and ambient light
+	specular  color.Color // color of the highlights
+	emissive  color.Color // color the surface gives off by itself
+	shininess float32     // size of the highlights, the higher the smaller
+	opacity   float32     // 0 is invisible, 1 is solid
+	texture   image.Image // optional, replaces the diffuse color
+}
+
+// NewMaterial creates a solid Material with the given diffuse color
+// It has soft grey highlights and gives off no light by itself
+func NewMaterial(diffuse color.Color) *Material {
+	return &Material{
+		diffuse:   diffuse,
+		specular:  color.Gray{0x4d},
+		emissive:  color.Black,
+		shininess: 32.0,
+		opacity:   1.0,
+	}
+}
+
+// DefaultMaterial provides the Material used for meshes that don't have one
+func DefaultMaterial() *Material {
+	return NewMaterial(color.White)
+}
+
+// SetDiffuse changes the color under direct and ambient light
+func (m *Material) SetDiffuse(diffuse color.Color) {
+	m.diffuse = diffuse
+}
+
+// SetSpecular changes the color of the highlights
+func (m *Material) SetSpecular(specular color.Color) {
+	m.specular = specular
+}
+
+// SetEmissive changes the color the surface gives off by itself
+func (m *Material) SetEmissive(emissive color.Color) {
+	m.emissive = emissive
+}
+
+// SetShininess changes the size of the highlights, the higher the smaller
+func (m *Material) SetShininess(shininess float32) {
+	if shininess < 0.0 {
+		log.Fatalf("Material.SetShininess: must be positive, got %f", shininess)
+	}
+	m.shininess = shininess
+}
+
+// SetOpacity changes how much of what is behind the surface shines through
+func (m *Material) SetOpacity(opacity float32) {
+	if opacity < 0.0 || opacity > 1.0 {
+		log.Fatalf("Material.SetOpacity: expects [0..1], got %f", opacity)
+	}
+	m.opacity = opacity
+}
+
+// SetTexture sets the image used instead of the diffuse color, nil removes it
+func (m *Material) SetTexture(texture image.Image) {
+	m.texture = texture
+}
+
+// GetDiffuse returns the color under direct and ambient light
+func (m *Material) GetDiffuse() color.Color {
+	return m.diffuse
+}
+
+// GetSpecular returns the color of the highlights
+func (m *Material) GetSpecular() color.Color {
+	return m.specular
+}
+
+// GetEmissive returns the color the surface gives off by itself
+func (m *Material) GetEmissive() color.Color {
+	return m.emissive
+}
+
+// GetShininess returns the size of the highlights
+func (m *Material) GetShininess() float32 {
+	return m.shininess
+}
+
+// GetOpacity returns how solid the surface is
+func (m *Material) GetOpacity() float32 {
+	return m.opacity
+}
+
+// GetTexture returns the image used instead of the diffuse color, if any
+func (m *Material) GetTexture() image.Image {
+	return m.texture
+}
@@ -2,35 +2,59 @@ package model
 
 import (
 	"fmt"
+	"image/color"
 	"log"
 	"math"
 	"reflect"
 
 	"../number/matrix"
+	"../number/quaternion"
 	"../number/vector"
 )
 
 // Mesh is a 3D triangle
+// Next to the positions of its vertices it can have a normal, texture coordinates (u, v)
+// and a color for every vertex, these are blended accross the triangle when it is drawn.
 type Mesh struct {
 	vertices [3]vector.Vector
+	normals  []vector.Vector // nil if the mesh has none, else one per vertex
+	uvs      []vector.Vector
+	colors   []color.Color
+	material *Material // nil uses the material of the part
 }
 
 // NewMesh creates a Mesh
 func NewMesh(vertices []vector.Vector) Mesh {
+	mesh, err := TryNewMesh(vertices)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return mesh
+}
+
+// TryNewMesh creates a Mesh like NewMesh, but reports a *Error instead of stopping
+// when the vertices don't make a triangle
+func TryNewMesh(vertices []vector.Vector) (Mesh, error) {
 	// Check size of the polygon, there should be at least three points
 	if len(vertices) != 3 {
-		log.Fatalf("Model.NewMesh: expected 3 points, got %d", len(vertices))
+		return Mesh{}, &Error{Op: "Model.NewMesh", Err: ErrVertexCount, Detail: fmt.Sprintf("got %d", len(vertices))}
 	}
 
 	var mesh Mesh
 	for i, point := range vertices {
+		if point == nil {
+			return Mesh{}, &Error{Op: "Model.NewMesh", Err: ErrDimension, Detail: "got nil"}
+		}
 		if point.Len() != 3 {
-			log.Fatalf("Model.NewMesh: expected 3D point, got %d", point.Len())
+			return Mesh{}, &Error{Op: "Model.NewMesh", Err: ErrDimension, Detail: fmt.Sprintf("got %d", point.Len())}
+		}
+		if point.Kind() != reflect.Float32 {
+			return Mesh{}, &Error{Op: "Model.NewMesh", Err: ErrKind, Detail: fmt.Sprintf("got %v", point.Kind())}
 		}
 		mesh.vertices[i] = point
 	}
 
-	return mesh
+	return mesh, nil
 }
 
 // GetVertex returns a vertex from a Mesh
@@ -41,23 +65,137 @@ func (m Mesh) GetVertex(index int) vector.Vector {
 	return m.vertices[index]
 }
 
+// SetNormals provides a copy of the Mesh with a 3D normal for every vertex, nil removes them
+// Without normals the triangle is lit as a flat surface.
+func (m Mesh) SetNormals(normals []vector.Vector) Mesh {
+	m.normals = checkAttributes("Mesh.SetNormals", normals, 3)
+	return m
+}
+
+// GetNormal returns the normal of a vertex, nil if the Mesh has none
+func (m Mesh) GetNormal(index int) vector.Vector {
+	if index < 0 || index > 2 {
+		log.Fatalf("Model.GetNormal: index out of range %d", index)
+	}
+	if m.normals == nil {
+		return nil
+	}
+	return m.normals[index]
+}
+
+// SetUVs provides a copy of the Mesh with 2D texture coordinates (u, v) for every vertex, nil removes them
+// (0, 0) is the bottom left and (1, 1) the top right of the texture.
+func (m Mesh) SetUVs(uvs []vector.Vector) Mesh {
+	m.uvs = checkAttributes("Mesh.SetUVs", uvs, 2)
+	return m
+}
+
+// GetUV returns the texture coordinates of a vertex, nil if the Mesh has none
+func (m Mesh) GetUV(index int) vector.Vector {
+	if index < 0 || index > 2 {
+		log.Fatalf("Model.GetUV: index out of range %d", index)
+	}
+	if m.uvs == nil {
+		return nil
+	}
+	return m.uvs[index]
+}
+
+// SetColors provides a copy of the Mesh with a color for every vertex, nil removes them
+// The colors tint the diffuse color of the material.
+func (m Mesh) SetColors(colors []color.Color) Mesh {
+	if colors == nil {
+		m.colors = nil
+		return m
+	}
+	if len(colors) != 3 {
+		log.Fatalf("Mesh.SetColors: expected 3 colors, got %d", len(colors))
+	}
+	for _, c := range colors {
+		if c == nil {
+			log.Fatalf("Mesh.SetColors: color can't be nil")
+		}
+	}
+	m.colors = append([]color.Color{}, colors...)
+	return m
+}
+
+// GetColor returns the color of a vertex, nil if the Mesh has none
+func (m Mesh) GetColor(index int) color.Color {
+	if index < 0 || index > 2 {
+		log.Fatalf("Model.GetColor: index out of range %d", index)
+	}
+	if m.colors == nil {
+		return nil
+	}
+	return m.colors[index]
+}
+
+// checkAttributes checks there is a Float32 vector of the given size for every vertex
+func checkAttributes(caller string, attributes []vector.Vector, size int) []vector.Vector {
+	if attributes == nil {
+		return nil
+	}
+	if len(attributes) != 3 {
+		log.Fatalf("%s: expected 3 vectors, got %d", caller, len(attributes))
+	}
+	for _, a := range attributes {
+		if a == nil || a.Len() != size || a.Kind() != reflect.Float32 {
+			log.Fatalf("%s: expects %dD-Float32 vectors, got %v", caller, size, a)
+		}
+	}
+	return append([]vector.Vector{}, attributes...)
+}
+
+// SetMaterial provides a copy of the Mesh with its own Material, overriding that of the part
+func (m Mesh) SetMaterial(material *Material) Mesh {
+	m.material = material
+	return m
+}
+
+// GetMaterial returns the Material of a Mesh, nil if it uses that of the part
+func (m Mesh) GetMaterial() *Material {
+	return m.material
+}
+
+// Normal provides the unit vector perpendicular to the Mesh
+// It points to the side from which the vertices are seen in counter-clockwise order
+func (m Mesh) Normal() vector.Vector {
+	normal := m.vertices[1].Sub(m.vertices[0]).Cross(m.vertices[2].Sub(m.vertices[0]))
+
+	// Degenerate triangles don't have a direction
+	if normal.Abs() == 0.0 {
+		return normal
+	}
+	return normal.Unit()
+}
+
 // Add stringer interface
 func (m Mesh) String() string {
 	return fmt.Sprintf("[\n\t%v\n\t%v\n\t%v\n]", m.GetVertex(0), m.GetVertex(1), m.GetVertex(2))
 }
 
 // Part represents a complex object
+// It can hold named sub-parts, positioned in the coordinate system of the part
 type Part struct {
 	position vector.Vector
 	rotation matrix.Matrix
 	scaling  matrix.Matrix
 	shearing matrix.Matrix
-	meshes   []Mesh
+	material *Material
+	geometry *Geometry
+	children []child
+}
+
+// child is a named sub-part
+type child struct {
+	name string
+	part *Part
 }
 
 // SetPosition moves the part arround in it's parents coordinate system
 func (p *Part) SetPosition(position vector.Vector) {
-	if position.Len() != 3 && position.Kind() != reflect.Float32 {
+	if position.Len() != 3 || position.Kind() != reflect.Float32 {
 		log.Fatalf("Part.SetPosition: expects 3D-Float32 vector, got %dD-%v", position.Len(), position.Kind())
 	}
 	p.position = position
@@ -65,55 +203,248 @@ func (p *Part) SetPosition(position vector.Vector) {
 
 // SetRotation moves the part arround inside it's own coordinate system
 func (p *Part) SetRotation(rotation vector.Vector) {
-	if rotation.Len() != 3 && rotation.Kind() != reflect.Float32 {
+	if rotation.Len() != 3 || rotation.Kind() != reflect.Float32 {
 		log.Fatalf("Part.SetRotation: expects 3D-Float32 vector, got %dD-%v", rotation.Len(), rotation.Kind())
 	}
-	// Make float64 values in Radians for math
-	xAngle := float64(rotation.Get(0).(float32)) * math.Pi / 180.0
-	yAngle := float64(rotation.Get(1).(float32)) * math.Pi / 180.0
-	zAngle := float64(rotation.Get(2).(float32)) * math.Pi / 180.0
-	// Create a rotation matrix for each axis
-	xRotation := matrix.NewMatrix([][]float32{
-		{1.0, 0.0, 0.0},
-		{0.0, float32(math.Cos(xAngle)), float32(-math.Sin(xAngle))},
-		{0.0, float32(math.Sin(xAngle)), float32(math.Cos(xAngle))},
-	})
-	yRotation := matrix.NewMatrix([][]float32{
-		{float32(math.Cos(yAngle)), 0.0, float32(math.Sin(yAngle))},
-		{0.0, 1.0, 0.0},
-		{float32(-math.Sin(yAngle)), 0.0, float32(math.Cos(yAngle))},
-	})
-	zRotation := matrix.NewMatrix([][]float32{
-		{float32(math.Cos(zAngle)), float32(-math.Sin(zAngle)), 0.0},
-		{float32(math.Sin(zAngle)), float32(math.Cos(zAngle)), 0.0},
-		{0.0, 0.0, 1.0},
-	})
-	// Combine the rotations
-	p.rotation = zRotation.Mulm(yRotation.Mulm(xRotation))
+	// Combine the rotations arround each axis
+	p.rotation = matrix.Linear(matrix.RotationZ(rotation.Get(2).(float32)).Mulm(
+		matrix.RotationY(rotation.Get(1).(float32)).Mulm(matrix.RotationX(rotation.Get(0).(float32)))))
+}
+
+// SetOrientation rotates the part inside it's own coordinate system like SetRotation, but with a quaternion
+// Unlike the angles arround each axis, quaternions don't gimbal-lock and interpolate smoothly.
+func (p *Part) SetOrientation(orientation quaternion.Quaternion) {
+	if err := p.TrySetOrientation(orientation); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// TrySetOrientation is SetOrientation, failing with quaternion.ErrZero or quaternion.ErrNotFinite
+// for an orientation that isn't a rotation, like one read from a file
+func (p *Part) TrySetOrientation(orientation quaternion.Quaternion) error {
+	rotation, err := orientation.TryMatrix()
+	if err != nil {
+		return err
+	}
+	p.rotation = rotation
+	return nil
+}
+
+// GetOrientation returns the rotation of the part as a unit quaternion
+func (p *Part) GetOrientation() quaternion.Quaternion {
+	p.transform()
+	return quaternion.FromMatrix(p.rotation)
 }
 
 // SetScale scales the part within it's own coordinate system
 func (p *Part) SetScale(scale vector.Vector) {
-	if scale.Len() != 3 && scale.Kind() != reflect.Float32 {
+	if scale.Len() != 3 || scale.Kind() != reflect.Float32 {
 		log.Fatalf("Part.SetScale: expects 3D-Float32 vector, got %dD-%v", scale.Len(), scale.Kind())
 	}
-	// Create scaling matrix
-	p.scaling = matrix.NewMatrix([][]float32{
-		{scale.Get(0).(float32), 0.0, 0.0},
-		{0.0, scale.Get(1).(float32), 0.0},
-		{0.0, 0.0, scale.Get(2).(float32)},
-	})
+	p.scaling = matrix.Linear(matrix.Scale(scale))
 }
 
 // SetShear shears the part within it's own coordinate system
-func (p *Part) SetShear(sheer vector.Vector) {
-	log.Fatalf("Part.SetShear: not yet implemented")
+// The full specification is a 6D vector (xy, xz, yx, yz, zx, zy) where xy is how far x
+// moves for every unit of y and so on. A 3D vector (xy, xz, yz) only shears 'upwards',
+// which is the form DecomposeTransform provides.
+func (p *Part) SetShear(shear vector.Vector) {
+	if (shear.Len() != 3 && shear.Len() != 6) || shear.Kind() != reflect.Float32 {
+		log.Fatalf("Part.SetShear: expects 3D or 6D-Float32 vector, got %dD-%v", shear.Len(), shear.Kind())
+	}
+
+	var xy, xz, yx, yz, zx, zy float32
+	if shear.Len() == 3 {
+		xy, xz, yz = shear.Get(0).(float32), shear.Get(1).(float32), shear.Get(2).(float32)
+	} else {
+		xy, xz, yx = shear.Get(0).(float32), shear.Get(1).(float32), shear.Get(2).(float32)
+		yz, zx, zy = shear.Get(3).(float32), shear.Get(4).(float32), shear.Get(5).(float32)
+	}
+
+	// Create shearing matrix
+	p.shearing = matrix.NewMatrix([][]float32{
+		{1.0, xy, xz},
+		{yx, 1.0, yz},
+		{zx, zy, 1.0},
+	})
 }
 
-// GetMeshes returns a list of meshes for the entire part: scaled, sheared, rotated and positioned
-func (p *Part) GetMeshes() []Mesh {
+// GetTransform returns the combined scaling, shearing and rotation matrix of the part
+// together with its position
+func (p *Part) GetTransform() (matrix.Matrix, vector.Vector) {
+	return p.transform()
+}
+
+// GetMatrix returns the transformation of the part as one 4x4 matrix on homogeneous coordinates
+// It scales, shears, rotates and then moves to the position, see GetTransform.
+func (p *Part) GetMatrix() matrix.Matrix {
+	return matrix.Affine(p.transform())
+}
+
+// SetTransform sets position, rotation, scale and shear of the part from a combined 3x3
+// transformation matrix and a position, see DecomposeTransform
+func (p *Part) SetTransform(transform matrix.Matrix, position vector.Vector) {
+	if err := p.TrySetTransform(transform, position); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// TrySetTransform sets the transformation like SetTransform, but reports a *Error instead of stopping
+// when it can't be decomposed, leaving the part as it was
+func (p *Part) TrySetTransform(transform matrix.Matrix, position vector.Vector) error {
+	_, rotation, scale, shear, err := TryDecomposeTransform(transform, position)
+	if err != nil {
+		return err
+	}
+	p.SetPosition(position)
+	p.SetRotation(rotation)
+	p.SetScale(scale)
+	p.SetShear(shear)
+	return nil
+}
+
+// DecomposeTransform splits a combined transformation, as provided by GetTransform, into the
+// position, rotation (degrees arround x, y and z), scale and shear (xy, xz, yz) that create it
+// The decomposition is unique when the shear is 3D, a 6D shear comes back in its 3D form with
+// the difference taken up by the rotation and scale. A mirroring transformation gives a negative z scale.
+func DecomposeTransform(transform matrix.Matrix, position vector.Vector) (vector.Vector, vector.Vector, vector.Vector, vector.Vector) {
+	position, rotation, scale, shear, err := TryDecomposeTransform(transform, position)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return position, rotation, scale, shear
+}
+
+// TryDecomposeTransform splits a transformation like DecomposeTransform, but reports a *Error instead
+// of stopping, also for singular transformations like a zero scale that have no decomposition
+func TryDecomposeTransform(transform matrix.Matrix, position vector.Vector) (vector.Vector, vector.Vector, vector.Vector, vector.Vector, error) {
+	if transform.Rows() != 3 || transform.Cols() != 3 || transform.Kind() != reflect.Float32 {
+		return nil, nil, nil, nil, &Error{Op: "Model.DecomposeTransform", Err: ErrTransform, Detail: fmt.Sprintf("got %dx%d-%v", transform.Rows(), transform.Cols(), transform.Kind())}
+	}
+
+	// Split M into an orthonormal Q and an upper triangular U using Gram-Schmidt on the columns
+	var m, q, u [3][3]float64
+	for r := 0; r < 3; r++ {
+		for c := 0; c < 3; c++ {
+			m[r][c] = float64(transform.Get(r, c).(float32))
+		}
+	}
+	for c := 0; c < 3; c++ {
+		column := [3]float64{m[0][c], m[1][c], m[2][c]}
+		for k := 0; k < c; k++ {
+			u[k][c] = q[0][k]*m[0][c] + q[1][k]*m[1][c] + q[2][k]*m[2][c]
+			for r := 0; r < 3; r++ {
+				column[r] -= u[k][c] * q[r][k]
+			}
+		}
+		u[c][c] = math.Sqrt(column[0]*column[0] + column[1]*column[1] + column[2]*column[2])
+		if u[c][c] == 0.0 {
+			return nil, nil, nil, nil, &Error{Op: "Model.DecomposeTransform", Err: ErrSingular, Detail: fmt.Sprintf("column %d depends on the others", c)}
+		}
+		for r := 0; r < 3; r++ {
+			q[r][c] = column[r] / u[c][c]
+		}
+	}
+
+	// A proper rotation has determinant 1, move any mirroring into the scale
+	det := q[0][0]*(q[1][1]*q[2][2]-q[1][2]*q[2][1]) -
+		q[0][1]*(q[1][0]*q[2][2]-q[1][2]*q[2][0]) +
+		q[0][2]*(q[1][0]*q[2][1]-q[1][1]*q[2][0])
+	if det < 0.0 {
+		for k := 0; k < 3; k++ {
+			q[k][2] = -q[k][2]
+			u[2][k] = -u[2][k]
+		}
+	}
+
+	// U is the shear (unit upper triangular) times the scale (diagonal)
+	scale := vector.NewVector([]float32{float32(u[0][0]), float32(u[1][1]), float32(u[2][2])})
+	shear := vector.NewVector([]float32{float32(u[0][1] / u[1][1]), float32(u[0][2] / u[2][2]), float32(u[1][2] / u[2][2])})
+
+	// Q is Rz * Ry * Rx, take the angles out
+	var x, y, z float64
+	y = math.Asin(math.Max(-1.0, math.Min(1.0, -q[2][0])))
+	if math.Abs(math.Cos(y)) > 1e-6 {
+		x = math.Atan2(q[2][1], q[2][2])
+		z = math.Atan2(q[1][0], q[0][0])
+	} else {
+		// Gimbal lock, only the combination of x and z matters
+		x = math.Atan2(-q[1][2], q[1][1])
+	}
+	rotation := vector.NewVector([]float32{
+		float32(x * 180.0 / math.Pi), float32(y * 180.0 / math.Pi), float32(z * 180.0 / math.Pi),
+	})
+
+	return position, rotation, scale, shear, nil
+}
+
+// SetMaterial sets the Material for all meshes of the part that don't have their own
+func (p *Part) SetMaterial(material *Material) {
+	p.material = material
+}
 
-	// Set-up the translation matrix
+// GetMaterial returns the Material of the part, nil if it has none
+func (p *Part) GetMaterial() *Material {
+	return p.material
+}
+
+// AddPart attaches a named sub-part
+// Its position, rotation, scale and shear are relative to this part
+func (p *Part) AddPart(name string, part *Part) {
+	if part == nil {
+		log.Fatalf("Part.AddPart: part %q can't be nil", name)
+	}
+	if p.GetPart(name) != nil {
+		log.Fatalf("Part.AddPart: there already is a part %q", name)
+	}
+	if part == p || part.contains(p) {
+		log.Fatalf("Part.AddPart: part %q would contain itself", name)
+	}
+	p.children = append(p.children, child{name, part})
+}
+
+// GetPart returns the sub-part with the given name, nil if there is none
+func (p *Part) GetPart(name string) *Part {
+	for _, c := range p.children {
+		if c.name == name {
+			return c.part
+		}
+	}
+	return nil
+}
+
+// RemovePart detaches the sub-part with the given name
+func (p *Part) RemovePart(name string) {
+	for i, c := range p.children {
+		if c.name == name {
+			p.children = append(p.children[:i], p.children[i+1:]...)
+			return
+		}
+	}
+	log.Fatalf("Part.RemovePart: there is no part %q", name)
+}
+
+// GetPartNames returns the names of the sub-parts in the order they were added
+func (p *Part) GetPartNames() []string {
+	names := make([]string, len(p.children))
+	for i, c := range p.children {
+		names[i] = c.name
+	}
+	return names
+}
+
+// contains checks if part is somewhere in the tree below this part
+func (p *Part) contains(part *Part) bool {
+	for _, c := range p.children {
+		if c.part == part || c.part.contains(part) {
+			return true
+		}
+	}
+	return false
+}
+
+// transform returns the matrix and offset that place the part in its parent's coordinate system
+func (p *Part) transform() (matrix.Matrix, vector.Vector) {
 	if p.position == nil {
 		p.position = vector.ZeroVector(3, reflect.Float32)
 	}
@@ -126,24 +457,57 @@ func (p *Part) GetMeshes() []Mesh {
 	if p.shearing == nil {
 		p.shearing = matrix.UnitMatrix(3, 3, reflect.Float32)
 	}
-	translation := p.rotation.Mulm(p.shearing.Mulm(p.scaling))
+	return p.rotation.Mulm(p.shearing.Mulm(p.scaling)), p.position
+}
 
-	// TODO: process subparts
+// SetGeometry sets the triangles of the part itself, in its own coordinate system
+func (p *Part) SetGeometry(geometry *Geometry) {
+	p.geometry = geometry
+}
 
-	// scale, shear, rotate and reposition
-	result := make([]Mesh, len(p.meshes))
-	for index, mesh := range p.meshes {
-		this := NewMesh([]vector.Vector{
-			translation.Mulv(mesh.GetVertex(0)).Add(p.position),
-			translation.Mulv(mesh.GetVertex(1)).Add(p.position),
-			translation.Mulv(mesh.GetVertex(2)).Add(p.position),
-		})
-		result[index] = this
+// GetGeometry returns the triangles of the part itself, untransformed and without its sub-parts
+// The part starts with an empty Geometry that can be added to.
+func (p *Part) GetGeometry() *Geometry {
+	if p.geometry == nil {
+		p.geometry = NewGeometry()
 	}
+	return p.geometry
+}
 
+// GetTransformedGeometry returns the triangles of the entire part, including all sub-parts:
+// scaled, sheared, rotated and positioned, with every shared vertex transformed once
+func (p *Part) GetTransformedGeometry() *Geometry {
+	result := NewGeometry()
+	p.collectGeometry(matrix.UnitMatrix(4, 4, reflect.Float32), nil, result)
 	return result
 }
 
+// GetMeshes returns a list of meshes for the entire part, including all sub-parts:
+// scaled, sheared, rotated and positioned. Normals follow the surface they belong to.
+func (p *Part) GetMeshes() []Mesh {
+	return p.GetTransformedGeometry().Meshes()
+}
+
+// collectGeometry adds the geometry of the part and its sub-parts to result, placed by the
+// transformation of the parent. Triangles without a material get that of the nearest part that has one.
+func (p *Part) collectGeometry(parent matrix.Matrix, material *Material, result *Geometry) {
+
+	// Combine our own transformation with that of the parent
+	transform := parent.Mulm(p.GetMatrix())
+	if p.material != nil {
+		material = p.material
+	}
+
+	// scale, shear, rotate and reposition
+	if p.geometry != nil {
+		p.geometry.append(transform, material, result)
+	}
+
+	for _, c := range p.children {
+		c.part.collectGeometry(transform, material, result)
+	}
+}
+
 // Box is a simple example that implements the Part interface
 type Box struct {
 	Part
@@ -154,11 +518,20 @@ type Box struct {
 
 // NewBox creates a simple Box part
 func NewBox(width float32, depth float32, height float32) Box {
+	box, err := TryNewBox(width, depth, height)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return box
+}
+
+// TryNewBox creates a Box like NewBox, but reports a *Error instead of stopping on bad sizes
+func TryNewBox(width float32, depth float32, height float32) (Box, error) {
 	var box Box
 
 	// No negative stuff
 	if width <= 0.0 || depth <= 0.0 || height <= 0.0 {
-		log.Fatalf("Model.NewBox: must have positive sizes, got (w:%f, d:%f, h:%f)", width, depth, height)
+		return Box{}, &Error{Op: "Model.NewBox", Err: ErrSize, Detail: fmt.Sprintf("got (w:%f, d:%f, h:%f)", width, depth, height)}
 	}
 
 	// Corner points
@@ -171,31 +544,26 @@ func NewBox(width float32, depth float32, height float32) Box {
 	btr := vector.NewVector([]float32{width / 2.0, height, depth / 2.0})
 	ftr := vector.NewVector([]float32{width / 2.0, height, -depth / 2.0})
 
-	// Meshes from the points
-	box.meshes = []Mesh{
-		// Bottom
-		NewMesh([]vector.Vector{fbl, bbl, fbr}),
-		NewMesh([]vector.Vector{bbl, bbr, fbr}),
-		// Top
-		NewMesh([]vector.Vector{ftl, btl, ftr}),
-		NewMesh([]vector.Vector{btl, btr, ftr}),
-		// Left
-		NewMesh([]vector.Vector{fbl, bbl, btl}),
-		NewMesh([]vector.Vector{fbl, ftl, btl}),
-		// Right
-		NewMesh([]vector.Vector{fbr, bbr, btr}),
-		NewMesh([]vector.Vector{fbr, ftr, btr}),
-		// Front
-		NewMesh([]vector.Vector{fbl, ftl, ftr}),
-		NewMesh([]vector.Vector{fbl, fbr, ftr}),
-		// Back
-		NewMesh([]vector.Vector{bbl, btl, btr}),
-		NewMesh([]vector.Vector{bbl, bbr, btr}),
+	// Triangles between the corners, counter-clockwise seen from outside
+	geometry := NewGeometry()
+	for _, corner := range []vector.Vector{fbl, bbl, bbr, fbr, ftl, btl, btr, ftr} {
+		geometry.AddVertex(corner)
 	}
+	for _, t := range [][3]int{
+		{0, 3, 1}, {1, 3, 2}, // Bottom
+		{4, 5, 7}, {5, 6, 7}, // Top
+		{0, 1, 5}, {0, 5, 4}, // Left
+		{3, 6, 2}, {3, 7, 6}, // Right
+		{0, 4, 7}, {0, 7, 3}, // Front
+		{1, 6, 5}, {1, 2, 6}, // Back
+	} {
+		geometry.AddTriangle(t[0], t[1], t[2], nil)
+	}
+	box.geometry = geometry
 
 	box.width = width
 	box.depth = depth
 	box.height = height
 
-	return box
+	return box, nil
 }
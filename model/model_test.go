@@ -0,0 +1,210 @@
+package model
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	"../number/matrix"
+	"../number/quaternion"
+	"../number/vector"
+)
+
+// near compares two Float32 vectors allowing for rounding errors
+func near(v vector.Vector, w vector.Vector) bool {
+	return v.Sub(w).Abs() < 1e-4
+}
+
+func Test_SubParts(t *testing.T) {
+	// An arm with a hand at its end
+	arm := NewBox(10.0, 10.0, 100.0)
+	hand := NewBox(10.0, 10.0, 10.0)
+	hand.SetPosition(vector.NewVector([]float32{0.0, 100.0, 0.0}))
+	arm.AddPart("hand", &hand.Part)
+
+	meshes := arm.GetMeshes()
+	if len(meshes) != 24 {
+		t.Fatalf("Expected 24 meshes, got %d", len(meshes))
+	}
+
+	// The bottom of the hand sits on top of the arm
+	if !near(meshes[12].GetVertex(0), vector.NewVector([]float32{-5.0, 100.0, -5.0})) {
+		t.Errorf("Expected (-5, 100, -5), got %v", meshes[12].GetVertex(0))
+	}
+
+	// Turning the arm arround z takes the hand along
+	arm.SetRotation(vector.NewVector([]float32{0.0, 0.0, 90.0}))
+	arm.SetPosition(vector.NewVector([]float32{1.0, 2.0, 3.0}))
+	meshes = arm.GetMeshes()
+	if !near(meshes[12].GetVertex(0), vector.NewVector([]float32{-99.0, -3.0, -2.0})) {
+		t.Errorf("Expected (-99, -3, -2), got %v", meshes[12].GetVertex(0))
+	}
+
+	// Scaling the hand doesn't affect the arm
+	hand.SetScale(vector.NewVector([]float32{2.0, 2.0, 2.0}))
+	meshes = arm.GetMeshes()
+	if v := meshes[0].GetVertex(0); math.Abs(float64(v.Get(2).(float32))+2.0) > 1e-4 {
+		t.Errorf("Expected the arm to stay the same, got %v", v)
+	}
+
+	if names := arm.GetPartNames(); len(names) != 1 || names[0] != "hand" || arm.GetPart("hand") != &hand.Part {
+		t.Errorf("Expected the hand, got %v", names)
+	}
+	arm.RemovePart("hand")
+	if len(arm.GetMeshes()) != 12 {
+		t.Errorf("Expected the hand to be gone")
+	}
+}
+
+func Test_SubPartMaterial(t *testing.T) {
+	body := NewBox(10.0, 10.0, 10.0)
+	wheel := NewBox(1.0, 1.0, 1.0)
+	body.AddPart("wheel", &wheel.Part)
+
+	// Sub-parts inherit the material unless they have their own
+	paint := DefaultMaterial()
+	body.SetMaterial(paint)
+	if m := body.GetMeshes()[12].GetMaterial(); m != paint {
+		t.Errorf("Expected the material of the body, got %v", m)
+	}
+	rubber := DefaultMaterial()
+	wheel.SetMaterial(rubber)
+	if m := body.GetMeshes()[12].GetMaterial(); m != rubber {
+		t.Errorf("Expected the material of the wheel, got %v", m)
+	}
+}
+
+func Test_SetShear(t *testing.T) {
+	box := NewBox(2.0, 2.0, 2.0)
+
+	// x moves 1 for every unit of y
+	box.SetShear(vector.NewVector([]float32{1.0, 0.0, 0.0}))
+	meshes := box.GetMeshes()
+	if !near(meshes[2].GetVertex(0), vector.NewVector([]float32{1.0, 2.0, -1.0})) {
+		t.Errorf("Expected (1, 2, -1), got %v", meshes[2].GetVertex(0))
+	}
+
+	// z moves 0.5 for every unit of y
+	box.SetShear(vector.NewVector([]float32{0.0, 0.0, 0.0, 0.0, 0.0, 0.5}))
+	meshes = box.GetMeshes()
+	if !near(meshes[2].GetVertex(0), vector.NewVector([]float32{-1.0, 2.0, 0.0})) {
+		t.Errorf("Expected (-1, 2, 0), got %v", meshes[2].GetVertex(0))
+	}
+}
+
+func Test_DecomposeTransform(t *testing.T) {
+	part := NewBox(1.0, 1.0, 1.0)
+	position := vector.NewVector([]float32{1.0, 2.0, 3.0})
+	rotation := vector.NewVector([]float32{10.0, -20.0, 30.0})
+	scale := vector.NewVector([]float32{2.0, 3.0, 4.0})
+	shear := vector.NewVector([]float32{0.5, -0.25, 0.75})
+	part.SetPosition(position)
+	part.SetRotation(rotation)
+	part.SetScale(scale)
+	part.SetShear(shear)
+
+	transform, offset := part.GetTransform()
+	p, r, s, h := DecomposeTransform(transform, offset)
+	if !near(p, position) || !near(r, rotation) || !near(s, scale) || !near(h, shear) {
+		t.Errorf("Expected %v %v %v %v, got %v %v %v %v", position, rotation, scale, shear, p, r, s, h)
+	}
+
+	// Setting the transformation back gives the same result
+	other := NewBox(1.0, 1.0, 1.0)
+	other.SetTransform(transform, offset)
+	m0, m1 := part.GetMeshes(), other.GetMeshes()
+	for i := range m0 {
+		for j := 0; j < 3; j++ {
+			if !near(m0[i].GetVertex(j), m1[i].GetVertex(j)) {
+				t.Errorf("Expected %v, got %v", m0[i], m1[i])
+			}
+		}
+	}
+
+	// A zero scale can't be decomposed, the part stays as it was
+	before, _ := other.GetTransform()
+	flat := matrix.Linear(matrix.Scale(vector.NewVector([]float32{1.0, 0.0, 1.0})))
+	if err := other.TrySetTransform(flat, offset); !errors.Is(err, ErrSingular) {
+		t.Errorf("Expected %v, got %v", ErrSingular, err)
+	}
+	if m, _ := other.GetTransform(); !m.Equal(before) {
+		t.Errorf("Expected the part to keep\n%v, got\n%v", before, m)
+	}
+	if _, _, _, _, err := TryDecomposeTransform(matrix.UnitMatrix(4, 4, reflect.Float32), offset); !errors.Is(err, ErrTransform) {
+		t.Errorf("Expected %v, got %v", ErrTransform, err)
+	}
+}
+
+func Test_GetMatrix(t *testing.T) {
+	part := &Part{}
+	part.SetPosition(vector.NewVector([]float32{1.0, 2.0, 3.0}))
+	part.SetRotation(vector.NewVector([]float32{0.0, 0.0, 90.0}))
+	part.SetScale(vector.NewVector([]float32{2.0, 2.0, 2.0}))
+
+	// The matrix does the same as the transformation and position together
+	point := vector.NewVector([]float32{1.0, 0.0, 0.0})
+	transform, position := part.GetTransform()
+	expected := transform.Mulv(point).Add(position)
+	if got := vector.PerspectiveDivide(part.GetMatrix().Mulv(vector.Homogeneous(point))); !near(got, expected) || !near(got, vector.NewVector([]float32{1.0, 4.0, 3.0})) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
+
+func Test_TryNew(t *testing.T) {
+	point := vector.NewVector([]float32{0.0, 0.0, 0.0})
+	flat := vector.NewVector([]float32{0.0, 0.0})
+	tests := []struct {
+		err      error
+		expected error
+	}{
+		{second(TryNewMesh([]vector.Vector{point, point})), ErrVertexCount},
+		{second(TryNewMesh([]vector.Vector{point, point, flat})), ErrDimension},
+		{second(TryNewMesh([]vector.Vector{point, nil, point})), ErrDimension},
+		{second(TryNewMesh([]vector.Vector{point, point, vector.NewVector([]float64{0.0, 0.0, 1.0})})), ErrKind},
+		{second(TryNewBox(1.0, 0.0, 1.0)), ErrSize},
+	}
+	for i, test := range tests {
+		var e *Error
+		if !errors.Is(test.err, test.expected) || !errors.As(test.err, &e) {
+			t.Errorf("Test %d: expected %v, got %v", i, test.expected, test.err)
+		}
+	}
+
+	if box, err := TryNewBox(1.0, 2.0, 3.0); err != nil || box.GetGeometry().TriangleCount() != 12 {
+		t.Errorf("Expected a box, got %v", err)
+	}
+}
+
+// second drops the result of a constructor, keeping the error
+func second[T any](_ T, err error) error {
+	return err
+}
+
+func Test_Orientation(t *testing.T) {
+	var part Part
+	if q := part.GetOrientation(); !near(q.Vector(), quaternion.Identity().Vector()) {
+		t.Errorf("Expected no rotation, got %v", q)
+	}
+
+	rotation := vector.NewVector([]float32{30.0, 20.0, 10.0})
+	part.SetOrientation(quaternion.FromEuler(rotation))
+	_, angles, _, _ := DecomposeTransform(part.GetTransform())
+	if !near(angles, rotation) {
+		t.Errorf("Expected %v, got %v", rotation, angles)
+	}
+
+	var other Part
+	other.SetRotation(rotation)
+	if !near(part.GetOrientation().Vector(), other.GetOrientation().Vector()) {
+		t.Errorf("Expected %v, got %v", other.GetOrientation(), part.GetOrientation())
+	}
+
+	// A zero quaternion isn't a rotation and leaves the part as it was
+	if err := other.TrySetOrientation(quaternion.New(0.0, 0.0, 0.0, 0.0)); !errors.Is(err, quaternion.ErrZero) {
+		t.Errorf("Expected %v, got %v", quaternion.ErrZero, err)
+	}
+	if !near(part.GetOrientation().Vector(), other.GetOrientation().Vector()) {
+		t.Errorf("Expected %v, got %v", part.GetOrientation(), other.GetOrientation())
+	}
+}
@@ -0,0 +1,345 @@
+package model
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/jpeg" // textures referenced by material libraries
+	_ "image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"../number/vector"
+)
+
+// Library opens the files a model refers to, like material libraries and textures
+type Library func(name string) (io.ReadCloser, error)
+
+// DirectoryLibrary opens files relative to a directory
+// Names can't leave the directory, not with .. and not through symbolic links.
+func DirectoryLibrary(directory string) Library {
+	return func(name string) (io.ReadCloser, error) {
+		return os.OpenInRoot(directory, filepath.FromSlash(name))
+	}
+}
+
+// LoadOBJ reads a Wavefront OBJ file, with its material libraries and textures next to it
+func LoadOBJ(path string) (*Part, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadOBJ(f, DirectoryLibrary(filepath.Dir(path)))
+}
+
+// ReadOBJ reads a Wavefront OBJ model
+// Faces are triangulated as a fan, so polygons are expected to be convex. Faces before the
+// first group go into the part itself, every named group (g) or object (o) becomes a sub-part.
+// Material libraries (mtllib) are opened through library, which may be nil if there are none.
+func ReadOBJ(r io.Reader, library Library) (*Part, error) {
+	var positions, normals, uvs []vector.Vector
+	materials := make(map[string]*Material)
+	root := &Part{}
+	current := root
+	var material *Material
+
+	// Every part gets its own copy of the vertices it uses
+	shared := make(map[*Part]map[corner]int)
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		switch fields[0] {
+		case "v":
+			v, err := parseFloats(fields[1:], 3)
+			if err != nil {
+				return nil, fmt.Errorf("ReadOBJ: line %d: %w", line, err)
+			}
+			positions = append(positions, vector.NewVector(v))
+		case "vn":
+			v, err := parseFloats(fields[1:], 3)
+			if err != nil {
+				return nil, fmt.Errorf("ReadOBJ: line %d: %w", line, err)
+			}
+			normals = append(normals, vector.NewVector(v))
+		case "vt":
+			// 1D texture coordinates leave v at 0, a third coordinate w isn't used
+			values := fields[1:]
+			if len(values) == 1 {
+				values = []string{values[0], "0"}
+			}
+			v, err := parseFloats(values, 2)
+			if err != nil {
+				return nil, fmt.Errorf("ReadOBJ: line %d: %w", line, err)
+			}
+			uvs = append(uvs, vector.NewVector(v))
+		case "f":
+			triangles, err := parseFace(fields[1:], len(positions), len(normals), len(uvs))
+			if err != nil {
+				return nil, fmt.Errorf("ReadOBJ: line %d: %w", line, err)
+			}
+			if shared[current] == nil {
+				shared[current] = make(map[corner]int)
+			}
+			geometry := current.GetGeometry()
+			for _, t := range triangles {
+				var indices [3]int
+				for i, c := range t {
+					index, ok := shared[current][c]
+					if !ok {
+						index = geometry.AddVertex(positions[c.position])
+						if c.uv >= 0 {
+							geometry.SetUV(index, uvs[c.uv])
+						}
+						if c.normal >= 0 {
+							geometry.SetNormal(index, normals[c.normal])
+						}
+						shared[current][c] = index
+					}
+					indices[i] = index
+				}
+				geometry.AddTriangle(indices[0], indices[1], indices[2], material)
+			}
+		case "g", "o":
+			name := strings.Join(fields[1:], " ")
+			if name == "" || name == "default" {
+				current = root
+				continue
+			}
+			if current = root.GetPart(name); current == nil {
+				current = &Part{}
+				root.AddPart(name, current)
+			}
+		case "usemtl":
+			if len(fields) < 2 {
+				return nil, fmt.Errorf("ReadOBJ: line %d: usemtl without a name", line)
+			}
+			if material = materials[fields[1]]; material == nil {
+				return nil, fmt.Errorf("ReadOBJ: line %d: unknown material %q", line, fields[1])
+			}
+		case "mtllib":
+			if library == nil {
+				return nil, fmt.Errorf("ReadOBJ: line %d: no library to read %v from", line, fields[1:])
+			}
+			for _, name := range fields[1:] {
+				if err := readLibrary(name, library, materials); err != nil {
+					return nil, fmt.Errorf("ReadOBJ: line %d: %w", line, err)
+				}
+			}
+		default:
+			// Smoothing groups, lines, points and curves don't make triangles
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return root, nil
+}
+
+// ReadMTL reads a Wavefront material library
+// Textures (map_Kd) are opened through library, which may be nil if there are none.
+func ReadMTL(r io.Reader, library Library) (map[string]*Material, error) {
+	materials := make(map[string]*Material)
+	var current *Material
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if fields[0] == "newmtl" {
+			if len(fields) < 2 {
+				return nil, fmt.Errorf("ReadMTL: line %d: newmtl without a name", line)
+			}
+			current = DefaultMaterial()
+			materials[fields[1]] = current
+			continue
+		}
+		if current == nil {
+			return nil, fmt.Errorf("ReadMTL: line %d: %s before newmtl", line, fields[0])
+		}
+
+		var err error
+		switch fields[0] {
+		case "Kd":
+			current.diffuse, err = parseColor(fields[1:])
+		case "Ks":
+			current.specular, err = parseColor(fields[1:])
+		case "Ke":
+			current.emissive, err = parseColor(fields[1:])
+		case "Ns":
+			var v []float32
+			if v, err = parseFloats(fields[1:], 1); err == nil && v[0] < 0.0 {
+				err = fmt.Errorf("shininess must be positive, got %v", v[0])
+			} else if err == nil {
+				current.shininess = v[0]
+			}
+		case "d", "Tr":
+			var v []float32
+			if v, err = parseFloats(fields[1:], 1); err == nil {
+				current.opacity = clamp(v[0])
+				if fields[0] == "Tr" {
+					current.opacity = 1.0 - current.opacity
+				}
+			}
+		case "map_Kd":
+			if library == nil {
+				return nil, fmt.Errorf("ReadMTL: line %d: no library to read %v from", line, fields[1:])
+			}
+			// Options come before the file name, we don't support any
+			current.texture, err = readTexture(fields[len(fields)-1], library)
+		default:
+			// Ambient colors, illumination models and other maps aren't supported
+		}
+		if err != nil {
+			return nil, fmt.Errorf("ReadMTL: line %d: %w", line, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return materials, nil
+}
+
+// readLibrary adds the materials from a material library
+func readLibrary(name string, library Library, materials map[string]*Material) error {
+	f, err := library(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	found, err := ReadMTL(f, library)
+	if err != nil {
+		return err
+	}
+	for n, m := range found {
+		materials[n] = m
+	}
+	return nil
+}
+
+// readTexture loads a texture image
+func readTexture(name string, library Library) (image.Image, error) {
+	f, err := library(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	texture, _, err := image.Decode(f)
+	return texture, err
+}
+
+// corner refers to the position, texture coordinates and normal of a face corner, -1 if it has none
+type corner struct {
+	position int
+	uv       int
+	normal   int
+}
+
+// parseFace translates the corners of a face (v, v/vt, v//vn or v/vt/vn) into triangles
+func parseFace(corners []string, positions int, normals int, uvs int) ([][3]corner, error) {
+	if len(corners) < 3 {
+		return nil, fmt.Errorf("face needs at least 3 corners, got %d", len(corners))
+	}
+
+	points := make([]corner, len(corners))
+	for i, c := range corners {
+		indices := strings.Split(c, "/")
+		if len(indices) > 3 {
+			return nil, fmt.Errorf("invalid corner %q", c)
+		}
+		points[i] = corner{-1, -1, -1}
+		var err error
+		if points[i].position, err = parseIndex(indices[0], positions); err != nil {
+			return nil, err
+		}
+		if len(indices) > 1 && indices[1] != "" {
+			if points[i].uv, err = parseIndex(indices[1], uvs); err != nil {
+				return nil, err
+			}
+		}
+		if len(indices) > 2 && indices[2] != "" {
+			if points[i].normal, err = parseIndex(indices[2], normals); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	triangles := make([][3]corner, 0, len(points)-2)
+	for i := 1; i < len(points)-1; i++ {
+		triangles = append(triangles, [3]corner{points[0], points[i], points[i+1]})
+	}
+	return triangles, nil
+}
+
+// parseIndex translates a 1-based, or negative relative, index into a 0-based one
+func parseIndex(s string, count int) (int, error) {
+	i, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid index %q", s)
+	}
+	if i < 0 {
+		i += count
+	} else {
+		i--
+	}
+	if i < 0 || i >= count {
+		return 0, fmt.Errorf("index %s out of range, have %d", s, count)
+	}
+	return i, nil
+}
+
+// parseFloats reads count values, ignoring any optional ones after that
+func parseFloats(fields []string, count int) ([]float32, error) {
+	if len(fields) < count {
+		return nil, fmt.Errorf("expected %d values, got %d", count, len(fields))
+	}
+	values := make([]float32, count)
+	for i := range values {
+		v, err := strconv.ParseFloat(fields[i], 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", fields[i])
+		}
+		values[i] = float32(v)
+	}
+	return values, nil
+}
+
+// parseColor reads an r g b color with values between 0 and 1
+func parseColor(fields []string) (color.Color, error) {
+	v, err := parseFloats(fields, 3)
+	if err != nil {
+		return nil, err
+	}
+	return color.NRGBA{
+		uint8(clamp(v[0])*255.0 + 0.5),
+		uint8(clamp(v[1])*255.0 + 0.5),
+		uint8(clamp(v[2])*255.0 + 0.5),
+		0xff,
+	}, nil
+}
+
+// clamp keeps a value between 0 and 1
+func clamp(v float32) float32 {
+	switch {
+	case v < 0.0:
+		return 0.0
+	case v > 1.0:
+		return 1.0
+	}
+	return v
+}
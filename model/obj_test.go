@@ -0,0 +1,157 @@
+package model
+
+import (
+	"errors"
+	"image/color"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"../number/vector"
+)
+
+const testMTL = `# two materials
+newmtl red
+Kd 1.0 0.0 0.0
+Ns 10
+newmtl glass
+Kd 0.8 0.8 1.0
+d 0.25
+`
+
+const testOBJ = `# a square and a wheel
+mtllib test.mtl
+v 0 0 0
+v 1 0 0
+v 1 1 0
+v 0 1 0
+vt 0 0
+vt 1 0
+vt 1 1
+vn 0 0 1
+f 1/1/1 2/2/1 3/3/1 4/3/1
+g wheel
+usemtl red
+f -4 -3 -2
+usemtl glass
+f 1//1 3//1 4//1
+`
+
+// testLibrary serves files from memory
+func testLibrary(files map[string]string) Library {
+	return func(name string) (io.ReadCloser, error) {
+		content, ok := files[name]
+		if !ok {
+			return nil, os.ErrNotExist
+		}
+		return io.NopCloser(strings.NewReader(content)), nil
+	}
+}
+
+func Test_ReadOBJ(t *testing.T) {
+	part, err := ReadOBJ(strings.NewReader(testOBJ), testLibrary(map[string]string{"test.mtl": testMTL}))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// The quad is split in two, the wheel has its own part
+	if part.GetGeometry().TriangleCount() != 2 {
+		t.Errorf("Expected 2 meshes, got %d", part.GetGeometry().TriangleCount())
+	}
+	// Texture coordinates and normals go along with the positions
+	square := part.GetGeometry().Meshes()[0]
+	if square.GetUV(1) == nil || !square.GetUV(1).Equal(vector.NewVector([]float32{1.0, 0.0})) ||
+		square.GetNormal(1) == nil || !square.GetNormal(1).Equal(vector.NewVector([]float32{0.0, 0.0, 1.0})) {
+		t.Errorf("Expected uv (1, 0) and normal (0, 0, 1), got %v and %v", square.GetUV(1), square.GetNormal(1))
+	}
+	wheel := part.GetPart("wheel")
+	if wheel == nil || wheel.GetGeometry().TriangleCount() != 2 {
+		t.Fatalf("Expected a wheel with 2 meshes, got %v", wheel)
+	}
+	meshes := wheel.GetGeometry().Meshes()
+	if !meshes[0].GetVertex(2).Equal(vector.NewVector([]float32{1.0, 1.0, 0.0})) {
+		t.Errorf("Expected relative index to give (1, 1, 0), got %v", meshes[0].GetVertex(2))
+	}
+
+	// Materials are taken from the library
+	red := meshes[0].GetMaterial()
+	if red == nil || red.GetDiffuse() != (color.NRGBA{0xff, 0x00, 0x00, 0xff}) || red.GetShininess() != 10.0 {
+		t.Errorf("Expected red, got %v", red)
+	}
+	if glass := meshes[1].GetMaterial(); glass == nil || glass.GetOpacity() != 0.25 {
+		t.Errorf("Expected glass, got %v", glass)
+	}
+	if len(part.GetMeshes()) != 4 {
+		t.Errorf("Expected 4 meshes in total, got %d", len(part.GetMeshes()))
+	}
+}
+
+func Test_ReadOBJErrors(t *testing.T) {
+	bad := []string{
+		"v 0 0\n",
+		"v 0 0 0\nf 1 2 3\n",
+		"v 0 0 0\nv 1 0 0\nf 1 2\n",
+		"usemtl nothing\n",
+		"mtllib missing.mtl\n",
+		"mtllib negative.mtl\n",
+	}
+	for _, obj := range bad {
+		if _, err := ReadOBJ(strings.NewReader(obj), testLibrary(map[string]string{"negative.mtl": "newmtl dull\nNs -1\n"})); err == nil {
+			t.Errorf("Expected an error for %q", obj)
+		}
+	}
+
+	// Missing files can be told apart from broken ones
+	library := testLibrary(map[string]string{"test.mtl": "newmtl red\nmap_Kd missing.png\n"})
+	for _, obj := range []string{"mtllib missing.mtl\n", "mtllib test.mtl\n"} {
+		if _, err := ReadOBJ(strings.NewReader(obj), library); !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("Expected %v for %q, got %v", fs.ErrNotExist, obj, err)
+		}
+	}
+}
+
+func Test_ReadOBJTextureCoordinates(t *testing.T) {
+	obj := "v 0 0 0\nv 1 0 0\nv 0 1 0\nvt 0.25\nvt 0.5 0.75\nvt 1 1 0\nf 1/1 2/2 3/3\n"
+	part, err := ReadOBJ(strings.NewReader(obj), nil)
+	if err != nil {
+		t.Fatalf("Expected a model, got %v", err)
+	}
+	mesh := part.GetMeshes()[0]
+	if !mesh.GetUV(0).Equal(vector.NewVector([]float32{0.25, 0.0})) || !mesh.GetUV(2).Equal(vector.NewVector([]float32{1.0, 1.0})) {
+		t.Errorf("Expected (0.25, 0) and (1, 1), got %v and %v", mesh.GetUV(0), mesh.GetUV(2))
+	}
+	if _, err := ReadOBJ(strings.NewReader("vt\n"), nil); err == nil {
+		t.Errorf("Expected an error for vt without values")
+	}
+}
+
+func Test_DirectoryLibrary(t *testing.T) {
+	directory := t.TempDir()
+	if err := os.Mkdir(filepath.Join(directory, "models"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"secret.txt", "models/test.mtl"} {
+		if err := os.WriteFile(filepath.Join(directory, filepath.FromSlash(name)), []byte(testMTL), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	library := DirectoryLibrary(filepath.Join(directory, "models"))
+	f, err := library("test.mtl")
+	if err != nil {
+		t.Fatalf("Expected to open test.mtl, got %v", err)
+	}
+	f.Close()
+	for _, name := range []string{"../secret.txt", filepath.Join(directory, "secret.txt")} {
+		if f, err := library(name); err == nil {
+			f.Close()
+			t.Errorf("Expected %q to be outside the library", name)
+		}
+	}
+	if _, err := ReadOBJ(strings.NewReader("mtllib ../secret.txt\n"), library); err == nil {
+		t.Errorf("Expected an error for a material library outside the directory")
+	}
+}
@@ -0,0 +1,455 @@
+package model
+
+import (
+	"fmt"
+	"log"
+	"math"
+
+	"../number/vector"
+)
+
+// The primitives follow the Box: they stand on the xz plane at y = 0, centered on the y axis.
+// Their triangles are counter-clockwise seen from the outside, every vertex has a normal and
+// texture coordinates. Round shapes are tessellated into segments arround the y axis and
+// rings (or sides) along it.
+
+// Sphere is a UV sphere, made of rings of latitude
+type Sphere struct {
+	Part
+	radius   float32
+	segments int
+	rings    int
+}
+
+// NewSphere creates a Sphere, u follows the longitude and v runs from the bottom to the top
+func NewSphere(radius float32, segments int, rings int) Sphere {
+	sphere, err := TryNewSphere(radius, segments, rings)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return sphere
+}
+
+// TryNewSphere creates a Sphere like NewSphere, but reports a *Error instead of stopping on bad sizes or tessellation
+func TryNewSphere(radius float32, segments int, rings int) (Sphere, error) {
+	if radius <= 0.0 {
+		return Sphere{}, &Error{Op: "Model.NewSphere", Err: ErrSize, Detail: fmt.Sprintf("got (r:%f)", radius)}
+	}
+	if segments < 3 || rings < 2 {
+		return Sphere{}, &Error{Op: "Model.NewSphere", Err: ErrTessellation, Detail: fmt.Sprintf("expects 3+ segments and 2+ rings, got (s:%d, r:%d)", segments, rings)}
+	}
+
+	var sphere Sphere
+	sphere.radius, sphere.segments, sphere.rings = radius, segments, rings
+	profile := make([]profilePoint, rings+1)
+	for i := range profile {
+		sin, cos := sinCos(math.Pi * float64(i) / float64(rings))
+		profile[i] = profilePoint{radius * sin, radius * (1.0 - cos), sin, -cos, float32(i) / float32(rings)}
+	}
+	sphere.geometry = NewGeometry()
+	sphere.geometry.lathe(profile, segments)
+	return sphere, nil
+}
+
+// Icosphere is a sphere made of nearly equal triangles, by subdividing an icosahedron
+type Icosphere struct {
+	Part
+	radius       float32
+	subdivisions int
+}
+
+// NewIcosphere creates an Icosphere, every subdivision splits all triangles in four
+// The texture coordinates are the same as those of a Sphere.
+func NewIcosphere(radius float32, subdivisions int) Icosphere {
+	icosphere, err := TryNewIcosphere(radius, subdivisions)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return icosphere
+}
+
+// TryNewIcosphere creates an Icosphere like NewIcosphere, but reports a *Error instead of stopping on bad sizes or tessellation
+func TryNewIcosphere(radius float32, subdivisions int) (Icosphere, error) {
+	if radius <= 0.0 {
+		return Icosphere{}, &Error{Op: "Model.NewIcosphere", Err: ErrSize, Detail: fmt.Sprintf("got (r:%f)", radius)}
+	}
+	if subdivisions < 0 {
+		return Icosphere{}, &Error{Op: "Model.NewIcosphere", Err: ErrTessellation, Detail: fmt.Sprintf("expects 0+ subdivisions, got (s:%d)", subdivisions)}
+	}
+
+	// The icosahedron, corners on three golden rectangles
+	t := (1.0 + math.Sqrt(5.0)) / 2.0
+	points := [][3]float64{
+		{-1, t, 0}, {1, t, 0}, {-1, -t, 0}, {1, -t, 0},
+		{0, -1, t}, {0, 1, t}, {0, -1, -t}, {0, 1, -t},
+		{t, 0, -1}, {t, 0, 1}, {-t, 0, -1}, {-t, 0, 1},
+	}
+	faces := [][3]int{
+		{0, 11, 5}, {0, 5, 1}, {0, 1, 7}, {0, 7, 10}, {0, 10, 11},
+		{1, 5, 9}, {5, 11, 4}, {11, 10, 2}, {10, 7, 6}, {7, 1, 8},
+		{3, 9, 4}, {3, 4, 2}, {3, 2, 6}, {3, 6, 8}, {3, 8, 9},
+		{4, 9, 5}, {2, 4, 11}, {6, 2, 10}, {8, 6, 7}, {9, 8, 1},
+	}
+	for i, p := range points {
+		points[i] = unit3(p)
+	}
+
+	// Split every edge in the middle, sharing the new point between both sides
+	for s := 0; s < subdivisions; s++ {
+		middles := make(map[[2]int]int)
+		middle := func(a int, b int) int {
+			key := [2]int{a, b}
+			if b < a {
+				key = [2]int{b, a}
+			}
+			if m, ok := middles[key]; ok {
+				return m
+			}
+			points = append(points, unit3([3]float64{
+				points[a][0] + points[b][0], points[a][1] + points[b][1], points[a][2] + points[b][2],
+			}))
+			middles[key] = len(points) - 1
+			return len(points) - 1
+		}
+		split := make([][3]int, 0, len(faces)*4)
+		for _, f := range faces {
+			ab, bc, ca := middle(f[0], f[1]), middle(f[1], f[2]), middle(f[2], f[0])
+			split = append(split, [3]int{f[0], ab, ca}, [3]int{f[1], bc, ab}, [3]int{f[2], ca, bc}, [3]int{ab, bc, ca})
+		}
+		faces = split
+	}
+
+	var sphere Icosphere
+	sphere.radius, sphere.subdivisions = radius, subdivisions
+	sphere.geometry = NewGeometry()
+
+	// Corners share the vertex of their point, except where a triangle crosses the seam of the texture:
+	// there u wraps arround past 1, and those corners share a second vertex of the point
+	vertices := make(map[[2]int]int)
+	vertex := func(p int, u float64, wrapped int) int {
+		key := [2]int{p, wrapped}
+		if index, ok := vertices[key]; ok {
+			return index
+		}
+		v := 1.0 - math.Acos(points[p][1])/math.Pi
+		normal := vector.NewVector([]float32{float32(points[p][0]), float32(points[p][1]), float32(points[p][2])})
+		index := sphere.geometry.AddVertex(normal.Muls(radius).Add(vector.NewVector([]float32{0.0, radius, 0.0})))
+		sphere.geometry.SetNormal(index, normal)
+		sphere.geometry.SetUV(index, vector.NewVector([]float32{float32(u + float64(wrapped)), float32(v)}))
+		vertices[key] = index
+		return index
+	}
+	for _, f := range faces {
+		var us [3]float64
+		for i, p := range f {
+			if us[i] = math.Atan2(-points[p][2], points[p][0]) / (2.0 * math.Pi); us[i] < 0.0 {
+				us[i] += 1.0
+			}
+		}
+		var corners [3]int
+		for i, p := range f {
+			wrapped := 0
+			if math.Max(us[0], math.Max(us[1], us[2]))-us[i] > 0.5 {
+				wrapped = 1
+			}
+			corners[i] = vertex(p, us[i], wrapped)
+		}
+		sphere.geometry.AddTriangle(corners[0], corners[1], corners[2], nil)
+	}
+	return sphere, nil
+}
+
+// Cylinder is a round column with flat caps
+type Cylinder struct {
+	Part
+	radius   float32
+	height   float32
+	segments int
+}
+
+// NewCylinder creates a Cylinder, the side gets u arround and v upwards, the caps are mapped from above
+func NewCylinder(radius float32, height float32, segments int) Cylinder {
+	cylinder, err := TryNewCylinder(radius, height, segments)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return cylinder
+}
+
+// TryNewCylinder creates a Cylinder like NewCylinder, but reports a *Error instead of stopping on bad sizes or tessellation
+func TryNewCylinder(radius float32, height float32, segments int) (Cylinder, error) {
+	if radius <= 0.0 || height <= 0.0 {
+		return Cylinder{}, &Error{Op: "Model.NewCylinder", Err: ErrSize, Detail: fmt.Sprintf("got (r:%f, h:%f)", radius, height)}
+	}
+	if segments < 3 {
+		return Cylinder{}, &Error{Op: "Model.NewCylinder", Err: ErrTessellation, Detail: fmt.Sprintf("expects 3+ segments, got (s:%d)", segments)}
+	}
+
+	var cylinder Cylinder
+	cylinder.radius, cylinder.height, cylinder.segments = radius, height, segments
+	cylinder.geometry = NewGeometry()
+	cylinder.geometry.lathe([]profilePoint{
+		{radius, 0.0, 1.0, 0.0, 0.0},
+		{radius, height, 1.0, 0.0, 1.0},
+	}, segments)
+	cylinder.geometry.disk(radius, 0.0, -1.0, segments)
+	cylinder.geometry.disk(radius, height, 1.0, segments)
+	return cylinder, nil
+}
+
+// Cone is a round spike on a flat base
+type Cone struct {
+	Part
+	radius   float32
+	height   float32
+	segments int
+}
+
+// NewCone creates a Cone with its point up, the side gets u arround and v upwards
+func NewCone(radius float32, height float32, segments int) Cone {
+	cone, err := TryNewCone(radius, height, segments)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return cone
+}
+
+// TryNewCone creates a Cone like NewCone, but reports a *Error instead of stopping on bad sizes or tessellation
+func TryNewCone(radius float32, height float32, segments int) (Cone, error) {
+	if radius <= 0.0 || height <= 0.0 {
+		return Cone{}, &Error{Op: "Model.NewCone", Err: ErrSize, Detail: fmt.Sprintf("got (r:%f, h:%f)", radius, height)}
+	}
+	if segments < 3 {
+		return Cone{}, &Error{Op: "Model.NewCone", Err: ErrTessellation, Detail: fmt.Sprintf("expects 3+ segments, got (s:%d)", segments)}
+	}
+
+	// The side leans in, so the normals lean up
+	slope := float32(math.Hypot(float64(radius), float64(height)))
+	var cone Cone
+	cone.radius, cone.height, cone.segments = radius, height, segments
+	cone.geometry = NewGeometry()
+	cone.geometry.lathe([]profilePoint{
+		{radius, 0.0, height / slope, radius / slope, 0.0},
+		{0.0, height, height / slope, radius / slope, 1.0},
+	}, segments)
+	cone.geometry.disk(radius, 0.0, -1.0, segments)
+	return cone, nil
+}
+
+// Torus is a ring, lying flat
+type Torus struct {
+	Part
+	major    float32
+	minor    float32
+	segments int
+	sides    int
+}
+
+// NewTorus creates a Torus, major is the radius of the ring and minor that of the tube
+// u runs arround the ring and v arround the tube, starting on the outside.
+func NewTorus(major float32, minor float32, segments int, sides int) Torus {
+	torus, err := TryNewTorus(major, minor, segments, sides)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return torus
+}
+
+// TryNewTorus creates a Torus like NewTorus, but reports a *Error instead of stopping on bad sizes or tessellation
+func TryNewTorus(major float32, minor float32, segments int, sides int) (Torus, error) {
+	if minor <= 0.0 || major <= minor {
+		return Torus{}, &Error{Op: "Model.NewTorus", Err: ErrSize, Detail: fmt.Sprintf("expects 0 < minor < major, got (R:%f, r:%f)", major, minor)}
+	}
+	if segments < 3 || sides < 3 {
+		return Torus{}, &Error{Op: "Model.NewTorus", Err: ErrTessellation, Detail: fmt.Sprintf("expects 3+ segments and sides, got (s:%d, s:%d)", segments, sides)}
+	}
+
+	var torus Torus
+	torus.major, torus.minor, torus.segments, torus.sides = major, minor, segments, sides
+	profile := make([]profilePoint, sides+1)
+	for i := range profile {
+		sin, cos := sinCos(2.0 * math.Pi * float64(i) / float64(sides))
+		profile[i] = profilePoint{major + minor*cos, minor + minor*sin, cos, sin, float32(i) / float32(sides)}
+	}
+	torus.geometry = NewGeometry()
+	torus.geometry.lathe(profile, segments)
+	return torus, nil
+}
+
+// Plane is a flat rectangle facing up, divided into a grid
+type Plane struct {
+	Part
+	width   float32
+	depth   float32
+	columns int
+	rows    int
+}
+
+// NewPlane creates a Plane of columns by rows squares, u runs along x and v along -z
+func NewPlane(width float32, depth float32, columns int, rows int) Plane {
+	plane, err := TryNewPlane(width, depth, columns, rows)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return plane
+}
+
+// TryNewPlane creates a Plane like NewPlane, but reports a *Error instead of stopping on bad sizes or tessellation
+func TryNewPlane(width float32, depth float32, columns int, rows int) (Plane, error) {
+	if width <= 0.0 || depth <= 0.0 {
+		return Plane{}, &Error{Op: "Model.NewPlane", Err: ErrSize, Detail: fmt.Sprintf("got (w:%f, d:%f)", width, depth)}
+	}
+	if columns < 1 || rows < 1 {
+		return Plane{}, &Error{Op: "Model.NewPlane", Err: ErrTessellation, Detail: fmt.Sprintf("expects 1+ columns and rows, got (c:%d, r:%d)", columns, rows)}
+	}
+
+	var plane Plane
+	plane.width, plane.depth, plane.columns, plane.rows = width, depth, columns, rows
+	plane.geometry = NewGeometry()
+	up := vector.NewVector([]float32{0.0, 1.0, 0.0})
+	for r := 0; r <= rows; r++ {
+		for c := 0; c <= columns; c++ {
+			u, v := float32(c)/float32(columns), float32(r)/float32(rows)
+			index := plane.geometry.AddVertex(vector.NewVector([]float32{(u - 0.5) * width, 0.0, (0.5 - v) * depth}))
+			plane.geometry.SetNormal(index, up)
+			plane.geometry.SetUV(index, vector.NewVector([]float32{u, v}))
+		}
+	}
+	plane.geometry.quads(columns, rows, 0)
+	return plane, nil
+}
+
+// Capsule is a cylinder with half spheres on both ends
+type Capsule struct {
+	Part
+	radius   float32
+	height   float32
+	segments int
+	rings    int
+}
+
+// NewCapsule creates a Capsule, height includes the half spheres and rings is the number of rings of each of them
+// u runs arround and v from the bottom to the top.
+func NewCapsule(radius float32, height float32, segments int, rings int) Capsule {
+	capsule, err := TryNewCapsule(radius, height, segments, rings)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return capsule
+}
+
+// TryNewCapsule creates a Capsule like NewCapsule, but reports a *Error instead of stopping on bad sizes or tessellation
+func TryNewCapsule(radius float32, height float32, segments int, rings int) (Capsule, error) {
+	if radius <= 0.0 || height < 2.0*radius {
+		return Capsule{}, &Error{Op: "Model.NewCapsule", Err: ErrSize, Detail: fmt.Sprintf("expects height >= 2*radius > 0, got (r:%f, h:%f)", radius, height)}
+	}
+	if segments < 3 || rings < 1 {
+		return Capsule{}, &Error{Op: "Model.NewCapsule", Err: ErrTessellation, Detail: fmt.Sprintf("expects 3+ segments and 1+ rings, got (s:%d, r:%d)", segments, rings)}
+	}
+
+	var capsule Capsule
+	capsule.radius, capsule.height, capsule.segments, capsule.rings = radius, height, segments, rings
+	var profile []profilePoint
+	for i := 0; i <= rings; i++ {
+		sin, cos := sinCos(math.Pi / 2.0 * float64(i) / float64(rings))
+		profile = append(profile, profilePoint{radius * sin, radius * (1.0 - cos), sin, -cos, 0.0})
+	}
+	for i := 0; i <= rings; i++ {
+		sin, cos := sinCos(math.Pi / 2.0 * float64(i) / float64(rings))
+		profile = append(profile, profilePoint{radius * cos, height - radius + radius*sin, cos, sin, 0.0})
+	}
+	for i := range profile {
+		profile[i].v = profile[i].y / height
+	}
+	capsule.geometry = NewGeometry()
+	capsule.geometry.lathe(profile, segments)
+	return capsule, nil
+}
+
+// profilePoint is a point on the outline of a round shape, with its normal and v texture coordinate
+type profilePoint struct {
+	radius  float32
+	y       float32
+	normalR float32 // away from the y axis
+	normalY float32
+	v       float32
+}
+
+// lathe adds the surface made by turning a profile arround the y axis
+// The first and last segment meet at u = 0 and 1, the texture seam is at +x.
+func (g *Geometry) lathe(profile []profilePoint, segments int) {
+	first := len(g.vertices)
+	for _, p := range profile {
+		for s := 0; s <= segments; s++ {
+			u := float32(s) / float32(segments)
+			sin, cos := sinCos(2.0 * math.Pi * float64(s) / float64(segments))
+			index := g.AddVertex(vector.NewVector([]float32{p.radius * cos, p.y, -p.radius * sin}))
+			g.SetNormal(index, vector.NewVector([]float32{p.normalR * cos, p.normalY, -p.normalR * sin}).Unit())
+			g.SetUV(index, vector.NewVector([]float32{u, p.v}))
+		}
+	}
+
+	// At a pole the ring is a single point, and one triangle of every square has no area
+	for r := 0; r < len(profile)-1; r++ {
+		for s := 0; s < segments; s++ {
+			a := first + r*(segments+1) + s
+			b, d := a+1, a+segments+1
+			if profile[r].radius != 0.0 {
+				g.AddTriangle(a, b, d+1, nil)
+			}
+			if profile[r+1].radius != 0.0 {
+				g.AddTriangle(a, d+1, d, nil)
+			}
+		}
+	}
+}
+
+// disk adds a flat round cap at height y, facing up (1) or down (-1)
+// The texture is mapped from above.
+func (g *Geometry) disk(radius float32, y float32, facing float32, segments int) {
+	normal := vector.NewVector([]float32{0.0, facing, 0.0})
+	center := g.AddVertex(vector.NewVector([]float32{0.0, y, 0.0}))
+	g.SetNormal(center, normal)
+	g.SetUV(center, vector.NewVector([]float32{0.5, 0.5}))
+	for s := 0; s < segments; s++ {
+		sin, cos := sinCos(2.0 * math.Pi * float64(s) / float64(segments))
+		index := g.AddVertex(vector.NewVector([]float32{radius * cos, y, -radius * sin}))
+		g.SetNormal(index, normal)
+		g.SetUV(index, vector.NewVector([]float32{0.5 + 0.5*cos, 0.5 + 0.5*sin}))
+	}
+	for s := 0; s < segments; s++ {
+		// The ring turns counter-clockwise seen from above
+		next := center + 1 + (s+1)%segments
+		if facing > 0.0 {
+			g.AddTriangle(center, center+1+s, next, nil)
+		} else {
+			g.AddTriangle(center, next, center+1+s, nil)
+		}
+	}
+}
+
+// quads adds two triangles for every square of a grid of (columns+1) x (rows+1) vertices, starting at first
+// The rows follow the columns counter-clockwise, seen from the side the grid faces.
+func (g *Geometry) quads(columns int, rows int, first int) {
+	for r := 0; r < rows; r++ {
+		for c := 0; c < columns; c++ {
+			a := first + r*(columns+1) + c
+			b, d := a+1, a+columns+1
+			g.AddTriangle(a, b, d+1, nil)
+			g.AddTriangle(a, d+1, d, nil)
+		}
+	}
+}
+
+// sinCos provides the sine and cosine of an angle, rounded so the seams and poles of round shapes
+// end up exactly on top of each other
+func sinCos(angle float64) (float32, float32) {
+	sin, cos := math.Sincos(angle)
+	return float32(math.Round(sin*1e9) / 1e9), float32(math.Round(cos*1e9) / 1e9)
+}
+
+// unit3 scales a point onto the unit sphere
+func unit3(p [3]float64) [3]float64 {
+	length := math.Sqrt(p[0]*p[0] + p[1]*p[1] + p[2]*p[2])
+	return [3]float64{p[0] / length, p[1] / length, p[2] / length}
+}
@@ -0,0 +1,113 @@
+package model
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"../number/vector"
+)
+
+// volume adds up the signed volumes of the tetrahedra between the origin and every triangle
+// It is only right for closed shapes with the triangles counter-clockwise seen from outside.
+func volume(meshes []Mesh) float64 {
+	result := 0.0
+	for _, mesh := range meshes {
+		a, b, c := mesh.GetVertex(0), mesh.GetVertex(1), mesh.GetVertex(2)
+		result += a.Mulv(b.Cross(c)) / 6.0
+	}
+	return result
+}
+
+func Test_Primitives(t *testing.T) {
+	sphere, icosphere := NewSphere(1.0, 64, 32), NewIcosphere(1.0, 4)
+	cylinder, cone := NewCylinder(1.0, 2.0, 64), NewCone(1.0, 3.0, 64)
+	torus, capsule := NewTorus(2.0, 0.5, 64, 32), NewCapsule(1.0, 4.0, 64, 16)
+	tests := []struct {
+		name     string
+		part     *Part
+		expected float64
+	}{
+		{"sphere", &sphere.Part, 4.0 / 3.0 * math.Pi},
+		{"icosphere", &icosphere.Part, 4.0 / 3.0 * math.Pi},
+		{"cylinder", &cylinder.Part, 2.0 * math.Pi},
+		{"cone", &cone.Part, math.Pi},
+		{"torus", &torus.Part, 2.0 * math.Pi * math.Pi * 2.0 * 0.25},
+		{"capsule", &capsule.Part, 4.0/3.0*math.Pi + 2.0*math.Pi},
+	}
+	for _, test := range tests {
+		meshes := test.part.GetMeshes()
+
+		// A positive volume close to the real thing means the triangles are closed and face out
+		if v := volume(meshes); math.Abs(v-test.expected) > 0.02*test.expected {
+			t.Errorf("%s: expected volume %f, got %f", test.name, test.expected, v)
+		}
+
+		// The normals of the vertices are on the same side as those of the triangles
+		for i, mesh := range meshes {
+			normal := mesh.Normal()
+			if normal.Abs() == 0.0 || mesh.GetUV(0) == nil {
+				t.Fatalf("%s: mesh %d is degenerate or has no texture coordinates", test.name, i)
+			}
+			for v := 0; v < 3; v++ {
+				if n := mesh.GetNormal(v); n == nil || n.Mulv(normal) <= 0.0 || math.Abs(n.Abs()-1.0) > 1e-4 {
+					t.Fatalf("%s: mesh %d has normal %v at %d, facing %v", test.name, i, n, v, normal)
+				}
+			}
+		}
+	}
+
+	// The icosphere shares its vertices, only those on the seam of the texture come twice
+	if g := icosphere.GetGeometry(); g.TriangleCount() != 20*256 || g.VertexCount() > 10*256+2+4*16 {
+		t.Errorf("Expected 5120 triangles on about 2562 vertices, got %d on %d", g.TriangleCount(), g.VertexCount())
+	}
+
+	// Everything stands on the floor
+	small := NewSphere(2.0, 8, 4)
+	bottom := small.GetGeometry().GetVertex(0)
+	if !near(bottom, vector.NewVector([]float32{0.0, 0.0, 0.0})) {
+		t.Errorf("Expected the sphere to touch the floor at the origin, got %v", bottom)
+	}
+
+	// The plane faces up with u along x and v along -z
+	plane := NewPlane(4.0, 2.0, 4, 2)
+	meshes := plane.GetMeshes()
+	if len(meshes) != 16 || !near(meshes[0].Normal(), vector.NewVector([]float32{0.0, 1.0, 0.0})) {
+		t.Errorf("Expected 16 meshes facing up, got %d facing %v", len(meshes), meshes[0].Normal())
+	}
+	geometry := plane.GetGeometry()
+	last := geometry.VertexCount() - 1
+	if !near(geometry.GetVertex(last), vector.NewVector([]float32{2.0, 0.0, -1.0})) || !geometry.GetUV(last).Equal(vector.NewVector([]float32{1.0, 1.0})) {
+		t.Errorf("Expected (1, 1) at (2, 0, -1), got %v at %v", geometry.GetUV(last), geometry.GetVertex(last))
+	}
+}
+
+func Test_TryNewPrimitives(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected error
+	}{
+		{second(TryNewSphere(0.0, 8, 4)), ErrSize},
+		{second(TryNewSphere(1.0, 2, 4)), ErrTessellation},
+		{second(TryNewIcosphere(-1.0, 0)), ErrSize},
+		{second(TryNewIcosphere(1.0, -1)), ErrTessellation},
+		{second(TryNewCylinder(1.0, 0.0, 8)), ErrSize},
+		{second(TryNewCone(1.0, 1.0, 2)), ErrTessellation},
+		{second(TryNewTorus(1.0, 1.0, 8, 8)), ErrSize},
+		{second(TryNewTorus(2.0, 1.0, 8, 2)), ErrTessellation},
+		{second(TryNewPlane(1.0, 1.0, 0, 1)), ErrTessellation},
+		{second(TryNewCapsule(1.0, 1.0, 8, 4)), ErrSize},
+		{second(TryNewCapsule(1.0, 4.0, 8, 0)), ErrTessellation},
+	}
+	for i, test := range tests {
+		var e *Error
+		if !errors.Is(test.err, test.expected) || !errors.As(test.err, &e) {
+			t.Errorf("Test %d: expected %v, got %v", i, test.expected, test.err)
+		}
+	}
+
+	// No subdivisions is the icosahedron itself
+	if icosphere, err := TryNewIcosphere(1.0, 0); err != nil || icosphere.GetGeometry().TriangleCount() != 20 {
+		t.Errorf("Expected an icosahedron, got %v", err)
+	}
+}
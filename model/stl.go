@@ -0,0 +1,165 @@
+package model
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"../number/vector"
+)
+
+// Sizes in a binary STL file
+const (
+	stlHeader = 80
+	stlFacet  = 50
+)
+
+// LoadSTL reads an ASCII or binary STL file
+func LoadSTL(path string) (*Part, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadSTL(f)
+}
+
+// ReadSTL reads an ASCII or binary STL model, every facet becomes a triangle
+// The facet normals in the file are ignored, they follow from the order of the vertices.
+// Facets share the vertices they have in common.
+func ReadSTL(r io.Reader) (*Part, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Binary files may start with 'solid' as well, the size tells them apart
+	if len(data) >= stlHeader+4 {
+		count := binary.LittleEndian.Uint32(data[stlHeader:])
+		if uint64(len(data)) == stlHeader+4+uint64(count)*stlFacet {
+			return readBinarySTL(data[stlHeader+4:], int(count))
+		}
+	}
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("solid")) {
+		return readASCIISTL(data)
+	}
+	return nil, fmt.Errorf("ReadSTL: neither ASCII nor binary STL")
+}
+
+// readBinarySTL reads the facets of a binary STL file
+func readBinarySTL(data []byte, count int) (*Part, error) {
+	part := &Part{}
+	geometry := part.GetGeometry()
+	for i := 0; i < count; i++ {
+		facet := data[i*stlFacet:]
+		var points [3]vector.Vector
+		for p := range points {
+			// The normal comes first
+			offset := 12 + p*12
+			points[p] = vector.NewVector([]float32{
+				math.Float32frombits(binary.LittleEndian.Uint32(facet[offset:])),
+				math.Float32frombits(binary.LittleEndian.Uint32(facet[offset+4:])),
+				math.Float32frombits(binary.LittleEndian.Uint32(facet[offset+8:])),
+			})
+		}
+		geometry.AddMesh(NewMesh(points[:]))
+	}
+	return part, nil
+}
+
+// readASCIISTL reads the facets of an ASCII STL file
+func readASCIISTL(data []byte) (*Part, error) {
+	part := &Part{}
+	var points []vector.Vector
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch fields[0] {
+		case "vertex":
+			v, err := parseFloats(fields[1:], 3)
+			if err != nil {
+				return nil, fmt.Errorf("ReadSTL: line %d: %w", line, err)
+			}
+			points = append(points, vector.NewVector(v))
+		case "outer":
+			points = points[:0]
+		case "endloop":
+			if len(points) != 3 {
+				return nil, fmt.Errorf("ReadSTL: line %d: facet needs 3 vertices, got %d", line, len(points))
+			}
+			part.GetGeometry().AddMesh(NewMesh(points))
+			points = nil
+		case "solid", "facet", "endfacet", "endsolid":
+		default:
+			return nil, fmt.Errorf("ReadSTL: line %d: unexpected %q", line, fields[0])
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return part, nil
+}
+
+// WriteSTL writes the transformed meshes of a part, including its sub-parts, as an ASCII STL model
+func WriteSTL(w io.Writer, name string, part *Part) error {
+	b := bufio.NewWriter(w)
+	fmt.Fprintf(b, "solid %s\n", name)
+	for _, mesh := range part.GetMeshes() {
+		fmt.Fprintf(b, "  facet normal %s\n", formatSTL(mesh.Normal()))
+		fmt.Fprintf(b, "    outer loop\n")
+		for i := 0; i < 3; i++ {
+			fmt.Fprintf(b, "      vertex %s\n", formatSTL(mesh.GetVertex(i)))
+		}
+		fmt.Fprintf(b, "    endloop\n")
+		fmt.Fprintf(b, "  endfacet\n")
+	}
+	fmt.Fprintf(b, "endsolid %s\n", name)
+	return b.Flush()
+}
+
+// WriteBinarySTL writes the transformed meshes of a part, including its sub-parts, as a binary STL model
+// The name goes into the header, which must not start with 'solid'.
+func WriteBinarySTL(w io.Writer, name string, part *Part) error {
+	if strings.HasPrefix(name, "solid") {
+		return fmt.Errorf("WriteBinarySTL: name can't start with 'solid', got %q", name)
+	}
+	meshes := part.GetMeshes()
+
+	b := bufio.NewWriter(w)
+	header := make([]byte, stlHeader+4)
+	copy(header[:stlHeader], name)
+	binary.LittleEndian.PutUint32(header[stlHeader:], uint32(len(meshes)))
+	b.Write(header)
+
+	// The attribute bytes at the end of every facet stay zero
+	facet := make([]byte, stlFacet)
+	for _, mesh := range meshes {
+		points := []vector.Vector{mesh.Normal(), mesh.GetVertex(0), mesh.GetVertex(1), mesh.GetVertex(2)}
+		for p, point := range points {
+			for i := 0; i < 3; i++ {
+				binary.LittleEndian.PutUint32(facet[p*12+i*4:], math.Float32bits(point.Get(i).(float32)))
+			}
+		}
+		b.Write(facet)
+	}
+	return b.Flush()
+}
+
+// formatSTL writes the coordinates of a 3D-Float32 vector in scientific notation
+func formatSTL(v vector.Vector) string {
+	return strconv.FormatFloat(float64(v.Get(0).(float32)), 'e', 6, 32) + " " +
+		strconv.FormatFloat(float64(v.Get(1).(float32)), 'e', 6, 32) + " " +
+		strconv.FormatFloat(float64(v.Get(2).(float32)), 'e', 6, 32)
+}
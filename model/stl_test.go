@@ -0,0 +1,67 @@
+package model
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"../number/vector"
+)
+
+func Test_STLRoundTrip(t *testing.T) {
+	box := NewBox(2.0, 4.0, 6.0)
+	box.SetPosition(vector.NewVector([]float32{1.0, 2.0, 3.0}))
+	box.SetRotation(vector.NewVector([]float32{0.0, 0.0, 45.0}))
+	expected := box.GetMeshes()
+
+	var ascii, binary bytes.Buffer
+	if err := WriteSTL(&ascii, "box", &box.Part); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := WriteBinarySTL(&binary, "box", &box.Part); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if binary.Len() != 84+50*len(expected) {
+		t.Errorf("Expected %d bytes, got %d", 84+50*len(expected), binary.Len())
+	}
+	if !strings.Contains(ascii.String(), "facet normal") {
+		t.Errorf("Expected facet normals, got %s", ascii.String())
+	}
+
+	for _, buffer := range []*bytes.Buffer{&ascii, &binary} {
+		part, err := ReadSTL(buffer)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		meshes := part.GetMeshes()
+		if len(meshes) != len(expected) {
+			t.Fatalf("Expected %d meshes, got %d", len(expected), len(meshes))
+		}
+		for i := range meshes {
+			for j := 0; j < 3; j++ {
+				if !near(meshes[i].GetVertex(j), expected[i].GetVertex(j)) {
+					t.Errorf("Expected %v, got %v", expected[i], meshes[i])
+				}
+			}
+		}
+	}
+}
+
+func Test_ReadSTLErrors(t *testing.T) {
+	bad := []string{
+		"not an stl file",
+		"solid x\nfacet normal 0 0 1\nouter loop\nvertex 0 0 0\nvertex 1 0 0\nendloop\nendfacet\nendsolid x\n",
+		"solid x\nfacet normal 0 0 1\nouter loop\nvertex 0 0 zero\n",
+	}
+	for _, stl := range bad {
+		if _, err := ReadSTL(strings.NewReader(stl)); err == nil {
+			t.Errorf("Expected an error for %q", stl)
+		}
+	}
+
+	// The reason a line can't be read is wrapped, not just quoted
+	if _, err := ReadSTL(strings.NewReader(bad[2])); errors.Unwrap(err) == nil {
+		t.Errorf("Expected a wrapped error, got %v", err)
+	}
+}
@@ -0,0 +1,326 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"../number/vector"
+)
+
+// Defect is a kind of problem with the triangles of a part
+type Defect int
+
+const (
+	// DegenerateTriangle is a triangle without area, it has no direction
+	DegenerateTriangle Defect = iota
+	// InconsistentWinding is a pair of neighbouring triangles facing opposite sides
+	InconsistentWinding
+	// NonManifoldEdge is an edge shared by more than two triangles
+	NonManifoldEdge
+	// OpenBoundary is an edge with a triangle on one side only, so the surface isn't closed
+	OpenBoundary
+)
+
+// String names the defect
+func (d Defect) String() string {
+	switch d {
+	case DegenerateTriangle:
+		return "degenerate triangle"
+	case InconsistentWinding:
+		return "inconsistent winding"
+	case NonManifoldEdge:
+		return "non-manifold edge"
+	case OpenBoundary:
+		return "open boundary"
+	}
+	return fmt.Sprintf("Defect(%d)", int(d))
+}
+
+// MeshError reports a defect in the geometry of a part
+// Triangle is the index of the triangle in the Geometry of the part, Edge holds the vertex
+// indices of the edge involved, if any. Part is the path of the sub-part, like "body/wheel".
+type MeshError struct {
+	Defect   Defect
+	Part     string
+	Triangle int
+	Edge     [2]int
+}
+
+// Error implements the error interface
+func (e *MeshError) Error() string {
+	where := fmt.Sprintf("triangle %d", e.Triangle)
+	if e.Defect != DegenerateTriangle {
+		where += fmt.Sprintf(", edge (%d, %d)", e.Edge[0], e.Edge[1])
+	}
+	if e.Part != "" {
+		where = e.Part + ": " + where
+	}
+	return fmt.Sprintf("Model.Validate: %s: %v", where, e.Defect)
+}
+
+// Validate checks that the triangles form closed surfaces, consistently wound
+// Vertices at the same position count as one, so seams for texture coordinates don't break the
+// surface. All defects are reported, joined into one error, each of them a *MeshError.
+func (g *Geometry) Validate() error {
+	var errs []error
+	for _, e := range g.check() {
+		errs = append(errs, e)
+	}
+	return errors.Join(errs...)
+}
+
+// Validate checks the geometry of the part and all of its sub-parts, see Geometry.Validate
+func (p *Part) Validate() error {
+	var errs []error
+	p.walk("", func(path string, part *Part) {
+		if part.geometry == nil {
+			return
+		}
+		for _, e := range part.geometry.check() {
+			e.Part = path
+			errs = append(errs, e)
+		}
+	})
+	return errors.Join(errs...)
+}
+
+// Orient turns the triangles of every connected surface so they are counter-clockwise seen
+// from the outside. Outside is where the normals point away from the middle of the surface,
+// so open surfaces get a best guess. Surfaces that can't be wound consistently, like a Möbius
+// strip, are reported as errors. Triangles only count as neighbours on manifold edges.
+func (g *Geometry) Orient() error {
+	var errs []error
+	for _, e := range g.orient() {
+		errs = append(errs, e)
+	}
+	return errors.Join(errs...)
+}
+
+// orient turns the triangles outward and reports the edges where that doesn't work out
+func (g *Geometry) orient() []*MeshError {
+	welded := g.weld()
+	edges := g.edges(welded)
+	done := make([]bool, len(g.triangles))
+	var result []*MeshError
+
+	for start := range g.triangles {
+		if done[start] || g.degenerate(start, welded) {
+			continue
+		}
+
+		// Walk over the neighbours, flipping them to match the triangle we came from
+		component := []int{start}
+		done[start] = true
+		for next := 0; next < len(component); next++ {
+			t := component[next]
+			for _, edge := range g.triangleEdges(t, welded) {
+				shared := edges[edge.key()]
+				if len(shared) != 2 {
+					continue
+				}
+				other := shared[0]
+				if other.triangle == t {
+					other = shared[1]
+				}
+				// Neighbours run along the shared edge in opposite directions
+				forward := g.hasEdge(t, edge, welded)
+				if done[other.triangle] {
+					if g.hasEdge(other.triangle, edge, welded) == forward {
+						result = append(result, &MeshError{InconsistentWinding, "", other.triangle, [2]int{edge.a, edge.b}})
+					}
+					continue
+				}
+				if g.hasEdge(other.triangle, edge, welded) == forward {
+					g.flip(other.triangle)
+				}
+				done[other.triangle] = true
+				component = append(component, other.triangle)
+			}
+		}
+
+		// The signed volume seen from the middle tells if the surface is inside out
+		var center [3]float64
+		for _, t := range component {
+			for _, v := range g.triangles[t].indices {
+				for i := range center {
+					center[i] += float64(g.vertices[v].Get(i).(float32)) / float64(3*len(component))
+				}
+			}
+		}
+		volume := 0.0
+		for _, t := range component {
+			var p [3][3]float64
+			for c, v := range g.triangles[t].indices {
+				for i := range center {
+					p[c][i] = float64(g.vertices[v].Get(i).(float32)) - center[i]
+				}
+			}
+			volume += vector.NewVector(p[0][:]).Mulv(vector.NewVector(p[1][:]).Cross(vector.NewVector(p[2][:])))
+		}
+		if volume < 0.0 {
+			for _, t := range component {
+				g.flip(t)
+			}
+		}
+	}
+	return result
+}
+
+// Orient turns the triangles of the part and all of its sub-parts outward, see Geometry.Orient
+func (p *Part) Orient() error {
+	var errs []error
+	p.walk("", func(path string, part *Part) {
+		if part.geometry == nil {
+			return
+		}
+		for _, e := range part.geometry.orient() {
+			e.Part = path
+			errs = append(errs, e)
+		}
+	})
+	return errors.Join(errs...)
+}
+
+// walk calls visit for the part and all of its sub-parts, with their path below the part
+func (p *Part) walk(path string, visit func(path string, part *Part)) {
+	visit(path, p)
+	for _, c := range p.children {
+		name := c.name
+		if path != "" {
+			name = path + "/" + c.name
+		}
+		c.part.walk(name, visit)
+	}
+}
+
+// edge is a pair of welded vertices, in the order a triangle runs along them
+type edge struct {
+	a int
+	b int
+}
+
+// key is the same for both directions
+func (e edge) key() edge {
+	if e.b < e.a {
+		return edge{e.b, e.a}
+	}
+	return e
+}
+
+// edgeUse is a triangle on an edge
+type edgeUse struct {
+	triangle int
+	forward  bool // the triangle runs from the lower to the higher vertex
+}
+
+// check finds all defects
+func (g *Geometry) check() []*MeshError {
+	welded := g.weld()
+	var result []*MeshError
+	for t := range g.triangles {
+		if g.degenerate(t, welded) {
+			result = append(result, &MeshError{DegenerateTriangle, "", t, [2]int{}})
+		}
+	}
+
+	// Report every edge once, on the last triangle that has it
+	edges := g.edges(welded)
+	for t := range g.triangles {
+		if g.degenerate(t, welded) {
+			continue
+		}
+		for _, e := range g.triangleEdges(t, welded) {
+			shared := edges[e.key()]
+			if shared[len(shared)-1].triangle != t {
+				continue
+			}
+			switch {
+			case len(shared) == 1:
+				result = append(result, &MeshError{OpenBoundary, "", t, [2]int{e.a, e.b}})
+			case len(shared) > 2:
+				result = append(result, &MeshError{NonManifoldEdge, "", t, [2]int{e.a, e.b}})
+			case shared[0].forward == shared[1].forward:
+				result = append(result, &MeshError{InconsistentWinding, "", t, [2]int{e.a, e.b}})
+			}
+		}
+	}
+	return result
+}
+
+// weld gives every vertex the index of the first vertex at the same position
+func (g *Geometry) weld() []int {
+	first := make(map[[3]float32]int)
+	welded := make([]int, len(g.vertices))
+	for i, v := range g.vertices {
+		key := [3]float32{v.Get(0).(float32), v.Get(1).(float32), v.Get(2).(float32)}
+		if f, ok := first[key]; ok {
+			welded[i] = f
+		} else {
+			first[key] = i
+			welded[i] = i
+		}
+	}
+	return welded
+}
+
+// edges collects the triangles on every edge, triangles without area don't count
+func (g *Geometry) edges(welded []int) map[edge][]edgeUse {
+	edges := make(map[edge][]edgeUse)
+	for t := range g.triangles {
+		if g.degenerate(t, welded) {
+			continue
+		}
+		for _, e := range g.triangleEdges(t, welded) {
+			edges[e.key()] = append(edges[e.key()], edgeUse{t, e.a < e.b})
+		}
+	}
+	return edges
+}
+
+// triangleEdges provides the edges of a triangle in its own direction
+func (g *Geometry) triangleEdges(t int, welded []int) [3]edge {
+	i := g.triangles[t].indices
+	a, b, c := welded[i[0]], welded[i[1]], welded[i[2]]
+	return [3]edge{{a, b}, {b, c}, {c, a}}
+}
+
+// hasEdge checks if the triangle runs along the edge in the same direction
+func (g *Geometry) hasEdge(t int, e edge, welded []int) bool {
+	for _, own := range g.triangleEdges(t, welded) {
+		if own == e {
+			return true
+		}
+	}
+	return false
+}
+
+// degenerate checks if a triangle uses a position twice or has no area
+func (g *Geometry) degenerate(t int, welded []int) bool {
+	i := g.triangles[t].indices
+	if welded[i[0]] == welded[i[1]] || welded[i[1]] == welded[i[2]] || welded[i[2]] == welded[i[0]] {
+		return true
+	}
+
+	return g.flat(i[0], i[1], i[2])
+}
+
+// flat checks if three vertices are on a line
+// The area is compared to the length of the edges, so small triangles are fine.
+func (g *Geometry) flat(a int, b int, c int) bool {
+	var ab, ac [3]float64
+	for k := range ab {
+		origin := float64(g.vertices[a].Get(k).(float32))
+		ab[k] = float64(g.vertices[b].Get(k).(float32)) - origin
+		ac[k] = float64(g.vertices[c].Get(k).(float32)) - origin
+	}
+	cross := vector.NewVector(ab[:]).Cross(vector.NewVector(ac[:])).Abs()
+	longest := math.Max(ab[0]*ab[0]+ab[1]*ab[1]+ab[2]*ab[2], ac[0]*ac[0]+ac[1]*ac[1]+ac[2]*ac[2])
+	return cross <= 1e-6*longest
+}
+
+// flip turns a triangle over
+func (g *Geometry) flip(t int) {
+	i := &g.triangles[t].indices
+	i[1], i[2] = i[2], i[1]
+}
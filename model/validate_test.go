@@ -0,0 +1,88 @@
+package model
+
+import (
+	"errors"
+	"testing"
+
+	"../number/vector"
+)
+
+// defects lists the defects in an error from Validate or Orient
+func defects(err error) []Defect {
+	var result []Defect
+	if err == nil {
+		return result
+	}
+	for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+		var meshError *MeshError
+		if errors.As(e, &meshError) {
+			result = append(result, meshError.Defect)
+		}
+	}
+	return result
+}
+
+func Test_Validate(t *testing.T) {
+	// All the primitives are closed and wound outward
+	box, sphere, icosphere := NewBox(1.0, 2.0, 3.0), NewSphere(1.0, 8, 4), NewIcosphere(1.0, 1)
+	cylinder, cone, torus := NewCylinder(1.0, 2.0, 8), NewCone(1.0, 2.0, 8), NewTorus(2.0, 1.0, 8, 6)
+	capsule := NewCapsule(1.0, 3.0, 8, 3)
+	for _, part := range []*Part{&box.Part, &sphere.Part, &icosphere.Part, &cylinder.Part, &cone.Part, &torus.Part, &capsule.Part} {
+		if err := part.Validate(); err != nil {
+			t.Errorf("Expected a valid part, got %v", err)
+		}
+	}
+
+	// A plane is open all arround
+	plane := NewPlane(1.0, 1.0, 2, 2)
+	if found := defects(plane.Validate()); len(found) != 8 || found[0] != OpenBoundary {
+		t.Errorf("Expected 8 open edges, got %v", found)
+	}
+
+	// Turning one triangle of a sub-part over is found, and fixed
+	wheel := NewBox(1.0, 1.0, 1.0)
+	box.AddPart("wheel", &wheel.Part)
+	wheel.geometry.flip(3)
+	err := box.Validate()
+	var meshError *MeshError
+	if found := defects(err); len(found) != 3 || found[0] != InconsistentWinding || !errors.As(err, &meshError) || meshError.Part != "wheel" {
+		t.Errorf("Expected 3 inconsistent edges in the wheel, got %v", err)
+	}
+	if err := box.Orient(); err != nil {
+		t.Errorf("Expected the box to be oriented, got %v", err)
+	}
+	if err := box.Validate(); err != nil || !near(wheel.GetMeshes()[3].Normal(), vector.NewVector([]float32{0.0, 1.0, 0.0})) {
+		t.Errorf("Expected a valid box with the top facing up, got %v", err)
+	}
+
+	// Inside out surfaces are turned over as a whole
+	for i := 0; i < sphere.geometry.TriangleCount(); i++ {
+		sphere.geometry.flip(i)
+	}
+	if err := sphere.Validate(); err != nil {
+		t.Errorf("Expected an inside out sphere to be consistent, got %v", err)
+	}
+	sphere.Orient()
+	meshes := sphere.GetMeshes()
+	if normal := meshes[0].Normal(); normal.Mulv(meshes[0].GetNormal(0)) <= 0.0 {
+		t.Errorf("Expected the sphere to face out, got %v", normal)
+	}
+
+	// Flat and duplicated triangles
+	var geometry Geometry
+	for _, p := range [][]float32{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}, {2, 0, 0}, {0, 0, 1}} {
+		geometry.AddVertex(vector.NewVector(p))
+	}
+	geometry.AddTriangle(0, 1, 3, nil)
+	geometry.AddTriangle(0, 1, 2, nil)
+	geometry.AddTriangle(1, 0, 4, nil)
+	geometry.AddTriangle(0, 1, 4, nil)
+	found := defects(geometry.Validate())
+	counts := make(map[Defect]int)
+	for _, d := range found {
+		counts[d]++
+	}
+	if counts[DegenerateTriangle] != 1 || counts[NonManifoldEdge] != 1 || counts[OpenBoundary] != 2 {
+		t.Errorf("Expected 1 degenerate triangle, 1 non-manifold edge and 2 open edges, got %v", found)
+	}
+}
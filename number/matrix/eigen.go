@@ -0,0 +1,115 @@
+package matrix
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+
+	"../vector"
+)
+
+// Eigen is the decomposition A = V * D * V^T of a symmetric Float32 or Float64 matrix A
+// The columns of V are the eigenvectors, orthonormal, and D is the diagonal matrix of eigenvalues,
+// largest first. For the covariance of a set of points the eigenvectors are the principal axes.
+// The calculation is done in float64 with Jacobi rotations, the results have the kind of A.
+type Eigen struct {
+	kind    reflect.Kind
+	size    int
+	values  []float64 // the eigenvalues, largest first
+	vectors []float64 // the eigenvectors as columns, row by row
+}
+
+// NewEigen decomposes a symmetric Float32 or Float64 matrix
+func NewEigen(m Matrix) (*Eigen, error) {
+	if m.Kind() != reflect.Float32 && m.Kind() != reflect.Float64 {
+		return nil, &Error{Op: "matrix.NewEigen", Err: ErrKind, Detail: fmt.Sprintf("expects Float32 or Float64, got %v", m.Kind())}
+	}
+	if m.Rows() != m.Cols() {
+		return nil, &Error{Op: "matrix.NewEigen", Err: ErrNotSquare, Detail: fmt.Sprintf("got %dx%d", m.Rows(), m.Cols())}
+	}
+
+	n := m.Rows()
+	a, largest := float64Values(m)
+	tolerance := largest * float64(n) * epsilon(m.Kind())
+	for r := 0; r < n; r++ {
+		for c := r + 1; c < n; c++ {
+			if math.Abs(a[r*n+c]-a[c*n+r]) > tolerance {
+				return nil, &Error{Op: "matrix.NewEigen", Err: ErrNotSymmetric, Detail: fmt.Sprintf("(%d, %d) and (%d, %d) differ", r, c, c, r)}
+			}
+		}
+	}
+
+	v := make([]float64, n*n)
+	for i := 0; i < n; i++ {
+		v[i*n+i] = 1.0
+	}
+
+	// Rotate away the values off the diagonal until they are rounding errors, V collects the rotations
+	for sweep := 0; sweep < maxSweeps; sweep++ {
+		off, total := 0.0, 0.0
+		for r := 0; r < n; r++ {
+			for c := 0; c < n; c++ {
+				total += a[r*n+c] * a[r*n+c]
+				if r != c {
+					off += a[r*n+c] * a[r*n+c]
+				}
+			}
+		}
+		if off <= total*epsilon(reflect.Float64)*epsilon(reflect.Float64) {
+			break
+		}
+
+		for p := 0; p < n-1; p++ {
+			for q := p + 1; q < n; q++ {
+				if a[p*n+q] == 0.0 {
+					continue
+				}
+				theta := (a[q*n+q] - a[p*n+p]) / (2.0 * a[p*n+q])
+				t := math.Copysign(1.0, theta) / (math.Abs(theta) + math.Hypot(1.0, theta))
+				c := 1.0 / math.Hypot(1.0, t)
+				s := c * t
+
+				// A' = J^T * A * J, turning the columns and then the rows
+				rotate(a, n, n, p, q, c, s)
+				for k := 0; k < n; k++ {
+					ap, aq := a[p*n+k], a[q*n+k]
+					a[p*n+k] = c*ap - s*aq
+					a[q*n+k] = s*ap + c*aq
+				}
+				a[p*n+q], a[q*n+p] = 0.0, 0.0
+				rotate(v, n, n, p, q, c, s)
+			}
+		}
+	}
+
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i int, j int) bool {
+		return a[order[i]*n+order[i]] > a[order[j]*n+order[j]]
+	})
+
+	eigen := &Eigen{m.Kind(), n, make([]float64, n), make([]float64, n*n)}
+	for i, c := range order {
+		eigen.values[i] = a[c*n+c]
+		for r := 0; r < n; r++ {
+			eigen.vectors[r*n+i] = v[r*n+c]
+		}
+	}
+
+	return eigen, nil
+}
+
+// Values provides the eigenvalues, largest first
+func (e *Eigen) Values() vector.Vector {
+	return newVector(e.values, e.kind)
+}
+
+// Vectors provides the matrix with the eigenvectors as columns, in the order of the eigenvalues
+func (e *Eigen) Vectors() Matrix {
+	return float64Matrix(e.size, e.size, e.kind, func(r int, c int) float64 {
+		return e.vectors[r*e.size+c]
+	})
+}
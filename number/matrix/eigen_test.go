@@ -0,0 +1,64 @@
+package matrix
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	"../vector"
+)
+
+func Test_Eigen(t *testing.T) {
+	a := NewMatrix([][]float64{
+		{4.0, 1.0, 2.0},
+		{1.0, 3.0, 0.0},
+		{2.0, 0.0, 5.0},
+	})
+	eigen, err := NewEigen(a)
+	if err != nil {
+		t.Fatalf("Expected a decomposition, got %v", err)
+	}
+	values, vectors := eigen.Values(), eigen.Vectors()
+	for i := 0; i < 3; i++ {
+		column := vector.NewVector([]float64{vectors.Get(0, i).(float64), vectors.Get(1, i).(float64), vectors.Get(2, i).(float64)})
+		if !nearVector(a.Mulv(column), column.Muls(values.Get(i)), 1e-12) {
+			t.Errorf("Expected A*v = %v*v for %v, got %v", values.Get(i), column, a.Mulv(column))
+		}
+		if i > 0 && values.Get(i).(float64) > values.Get(i-1).(float64) {
+			t.Errorf("Expected the largest value first, got %v", values)
+		}
+	}
+	if !nearMatrix(vectors.Transpose().Mulm(vectors), UnitMatrix(3, 3, reflect.Float64), 1e-12) {
+		t.Errorf("Expected orthonormal eigenvectors, got\n%v", vectors)
+	}
+	trace := values.Get(0).(float64) + values.Get(1).(float64) + values.Get(2).(float64)
+	if math.Abs(trace-12.0) > 1e-12 {
+		t.Errorf("Expected the eigenvalues to add up to the trace 12, got %v", values)
+	}
+
+	// The principal axes of points stretched along (1, 1, 0)
+	covariance := NewMatrix([][]float32{
+		{5.0, 4.0, 0.0},
+		{4.0, 5.0, 0.0},
+		{0.0, 0.0, 1.0},
+	})
+	eigen, err = NewEigen(covariance)
+	if err != nil {
+		t.Fatalf("Expected a decomposition, got %v", err)
+	}
+	if values := eigen.Values(); !nearVector(values, vector.NewVector([]float32{9.0, 1.0, 1.0}), 1e-5) {
+		t.Errorf("Expected [9, 1, 1], got %v", values)
+	}
+	axis := math.Abs(float64(eigen.Vectors().Get(0, 0).(float32)))
+	if math.Abs(axis-math.Sqrt(0.5)) > 1e-6 {
+		t.Errorf("Expected the first axis along (1, 1, 0), got\n%v", eigen.Vectors())
+	}
+
+	if _, err := NewEigen(NewMatrix([][]float64{{1.0, 2.0}, {3.0, 4.0}})); !errors.Is(err, ErrNotSymmetric) {
+		t.Errorf("Expected %v, got %v", ErrNotSymmetric, err)
+	}
+	if _, err := NewEigen(NewMatrix([][]float64{{1.0, 2.0}})); !errors.Is(err, ErrNotSquare) {
+		t.Errorf("Expected %v, got %v", ErrNotSquare, err)
+	}
+}
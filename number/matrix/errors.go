@@ -0,0 +1,23 @@
+package matrix
+
+import (
+	"errors"
+
+	"../../internal/failure"
+)
+
+// The reasons a matrix can't be made or used, check for them with errors.Is
+var (
+	ErrNotArray      = errors.New("expected an array or slice")
+	ErrEmpty         = errors.New("cannot create with zero rows or cols")
+	ErrKind          = errors.New("unsupported kind")
+	ErrIrregular     = errors.New("rows differ in length")
+	ErrNotSquare     = errors.New("expected a square matrix")
+	ErrSingular      = errors.New("matrix is singular")
+	ErrDimension     = errors.New("dimensions do not match")
+	ErrRankDeficient = errors.New("columns of the matrix depend on each other")
+	ErrNotSymmetric  = errors.New("expected a symmetric matrix")
+)
+
+// Error tells which matrix operation or decomposition failed and why, get it with errors.As
+type Error = failure.Error
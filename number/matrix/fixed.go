@@ -0,0 +1,387 @@
+package matrix
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"strings"
+
+	"../vector"
+)
+
+// Mat3 is a 3x3 Float32 matrix without reflection, stored row by row
+// The factories return it for 3x3 float32 values. Matrices share their values, so it is used
+// as a pointer like the values of the generic matrix.
+type Mat3 [9]float32
+
+// Mat4 is a 4x4 Float32 matrix without reflection, used for homogeneous transformations
+type Mat4 [16]float32
+
+// Mulv provides the matrix multiplied by a column vector
+func (m *Mat3) Mulv(v vector.Vector) vector.Vector {
+	return mulv32("Mat3.Mulv", m[:], 3, v)
+}
+
+// Mulm provides the product of two matrices
+func (m *Mat3) Mulm(n Matrix) Matrix {
+	return mulm32("Mat3.Mulm", m[:], 3, n)
+}
+
+// Transpose provides the matrix mirrored along its main diagonal
+func (m *Mat3) Transpose() Matrix {
+	r := &Mat3{}
+	transpose32(r[:], m[:], 3)
+	return r
+}
+
+// Add provides the sum of two matrices
+func (m *Mat3) Add(n Matrix) Matrix {
+	r := &Mat3{}
+	o := other32("Mat3.Add", 3, n)
+	for i := range r {
+		r[i] = m[i] + o[i]
+	}
+	return r
+}
+
+// Sub provides the difference of two matrices
+func (m *Mat3) Sub(n Matrix) Matrix {
+	r := &Mat3{}
+	o := other32("Mat3.Sub", 3, n)
+	for i := range r {
+		r[i] = m[i] - o[i]
+	}
+	return r
+}
+
+// Hadamard provides the element by element product of two matrices
+func (m *Mat3) Hadamard(n Matrix) Matrix {
+	r := &Mat3{}
+	o := other32("Mat3.Hadamard", 3, n)
+	for i := range r {
+		r[i] = m[i] * o[i]
+	}
+	return r
+}
+
+// Muls multiplies the matrix by a float32
+func (m *Mat3) Muls(s interface{}) Matrix {
+	r := &Mat3{}
+	f := value32("Mat3.Muls", s)
+	for i := range r {
+		r[i] = m[i] * f
+	}
+	return r
+}
+
+// Divs divides the matrix by a float32
+func (m *Mat3) Divs(s interface{}) Matrix {
+	r := &Mat3{}
+	f := value32("Mat3.Divs", s)
+	for i := range r {
+		r[i] = m[i] / f
+	}
+	return r
+}
+
+// Kind is always Float32
+func (m *Mat3) Kind() reflect.Kind {
+	return reflect.Float32
+}
+
+// Rows is always 3
+func (m *Mat3) Rows() int {
+	return 3
+}
+
+// Cols is always 3
+func (m *Mat3) Cols() int {
+	return 3
+}
+
+// Get provides a value from the matrix
+func (m *Mat3) Get(row int, col int) interface{} {
+	return m[index32("Mat3.Get", 3, row, col)]
+}
+
+// Set changes a value in the matrix
+func (m *Mat3) Set(row int, col int, value interface{}) {
+	m[index32("Mat3.Set", 3, row, col)] = value32("Mat3.Set", value)
+}
+
+// Equal checks if two matrices are the same
+func (m *Mat3) Equal(n Matrix) bool {
+	return equal32("Mat3.Equal", m[:], 3, n)
+}
+
+// String implements the Stringer interface
+func (m *Mat3) String() string {
+	return string32(m[:], 3)
+}
+
+// Mulv provides the matrix multiplied by a column vector
+func (m *Mat4) Mulv(v vector.Vector) vector.Vector {
+	return mulv32("Mat4.Mulv", m[:], 4, v)
+}
+
+// Mulm provides the product of two matrices
+func (m *Mat4) Mulm(n Matrix) Matrix {
+	return mulm32("Mat4.Mulm", m[:], 4, n)
+}
+
+// Transpose provides the matrix mirrored along its main diagonal
+func (m *Mat4) Transpose() Matrix {
+	r := &Mat4{}
+	transpose32(r[:], m[:], 4)
+	return r
+}
+
+// Add provides the sum of two matrices
+func (m *Mat4) Add(n Matrix) Matrix {
+	r := &Mat4{}
+	o := other32("Mat4.Add", 4, n)
+	for i := range r {
+		r[i] = m[i] + o[i]
+	}
+	return r
+}
+
+// Sub provides the difference of two matrices
+func (m *Mat4) Sub(n Matrix) Matrix {
+	r := &Mat4{}
+	o := other32("Mat4.Sub", 4, n)
+	for i := range r {
+		r[i] = m[i] - o[i]
+	}
+	return r
+}
+
+// Hadamard provides the element by element product of two matrices
+func (m *Mat4) Hadamard(n Matrix) Matrix {
+	r := &Mat4{}
+	o := other32("Mat4.Hadamard", 4, n)
+	for i := range r {
+		r[i] = m[i] * o[i]
+	}
+	return r
+}
+
+// Muls multiplies the matrix by a float32
+func (m *Mat4) Muls(s interface{}) Matrix {
+	r := &Mat4{}
+	f := value32("Mat4.Muls", s)
+	for i := range r {
+		r[i] = m[i] * f
+	}
+	return r
+}
+
+// Divs divides the matrix by a float32
+func (m *Mat4) Divs(s interface{}) Matrix {
+	r := &Mat4{}
+	f := value32("Mat4.Divs", s)
+	for i := range r {
+		r[i] = m[i] / f
+	}
+	return r
+}
+
+// Kind is always Float32
+func (m *Mat4) Kind() reflect.Kind {
+	return reflect.Float32
+}
+
+// Rows is always 4
+func (m *Mat4) Rows() int {
+	return 4
+}
+
+// Cols is always 4
+func (m *Mat4) Cols() int {
+	return 4
+}
+
+// Get provides a value from the matrix
+func (m *Mat4) Get(row int, col int) interface{} {
+	return m[index32("Mat4.Get", 4, row, col)]
+}
+
+// Set changes a value in the matrix
+func (m *Mat4) Set(row int, col int, value interface{}) {
+	m[index32("Mat4.Set", 4, row, col)] = value32("Mat4.Set", value)
+}
+
+// Equal checks if two matrices are the same
+func (m *Mat4) Equal(n Matrix) bool {
+	return equal32("Mat4.Equal", m[:], 4, n)
+}
+
+// String implements the Stringer interface
+func (m *Mat4) String() string {
+	return string32(m[:], 4)
+}
+
+// fixedMatrix provides a Mat3 or Mat4 for size x size float32 values, or nil
+func fixedMatrix(size int, values []float32) Matrix {
+	switch size {
+	case 3:
+		m := &Mat3{}
+		copy(m[:], values)
+		return m
+	case 4:
+		m := &Mat4{}
+		copy(m[:], values)
+		return m
+	}
+	return nil
+}
+
+// fixedRows provides a Mat3 or Mat4 for 3x3 or 4x4 float32 rows, or nil
+func fixedRows(rows [][]float32) Matrix {
+	size := len(rows)
+	if size != 3 && size != 4 {
+		return nil
+	}
+	values := make([]float32, 0, size*size)
+	for _, row := range rows {
+		if len(row) != size {
+			return nil
+		}
+		values = append(values, row...)
+	}
+	return fixedMatrix(size, values)
+}
+
+// float32s provides the values of a Float32 matrix row by row, the values themselves for Mat3 and Mat4
+func float32s(m Matrix) []float32 {
+	switch m := m.(type) {
+	case *Mat3:
+		return m[:]
+	case *Mat4:
+		return m[:]
+	}
+	values := make([]float32, m.Rows()*m.Cols())
+	for r := 0; r < m.Rows(); r++ {
+		for c := 0; c < m.Cols(); c++ {
+			values[r*m.Cols()+c] = m.Get(r, c).(float32)
+		}
+	}
+	return values
+}
+
+// mulv32 multiplies size x size float32 values by a column vector
+func mulv32(caller string, m []float32, size int, v vector.Vector) vector.Vector {
+	if v.Kind() != reflect.Float32 {
+		log.Fatalf("%s: expected vector type %v, got %v", caller, reflect.Float32, v.Kind())
+	}
+	if v.Len() != size {
+		log.Fatalf("%s: expected vector length %d, got %d", caller, size, v.Len())
+	}
+
+	values := vector.Float32s(v)
+	result := make([]float32, size)
+	for r := range result {
+		for c, value := range values {
+			result[r] += m[r*size+c] * value
+		}
+	}
+	return vector.NewVector(result)
+}
+
+// mulm32 multiplies size x size float32 values by a matrix
+func mulm32(caller string, m []float32, size int, n Matrix) Matrix {
+	if n.Kind() != reflect.Float32 {
+		log.Fatalf("%s: expected matrix type %v, got %v", caller, reflect.Float32, n.Kind())
+	}
+	if n.Rows() != size {
+		log.Fatalf("%s: expected matrix with %d rows, got %d", caller, size, n.Rows())
+	}
+
+	values := float32s(n)
+	cols := n.Cols()
+	result := make([]float32, size*cols)
+	for r := 0; r < size; r++ {
+		for k := 0; k < size; k++ {
+			for c := 0; c < cols; c++ {
+				result[r*cols+c] += m[r*size+k] * values[k*cols+c]
+			}
+		}
+	}
+	if cols == size {
+		return fixedMatrix(size, result)
+	}
+
+	product := ZeroMatrix(size, cols, reflect.Float32)
+	for r := 0; r < size; r++ {
+		for c := 0; c < cols; c++ {
+			product.Set(r, c, result[r*cols+c])
+		}
+	}
+	return product
+}
+
+// transpose32 mirrors size x size float32 values into r
+func transpose32(r []float32, m []float32, size int) {
+	for row := 0; row < size; row++ {
+		for col := 0; col < size; col++ {
+			r[col*size+row] = m[row*size+col]
+		}
+	}
+}
+
+// other32 checks that a matrix is size x size Float32 and provides its values
+func other32(caller string, size int, n Matrix) []float32 {
+	if n.Kind() != reflect.Float32 {
+		log.Fatalf("%s: expected matrix type %v, got %v", caller, reflect.Float32, n.Kind())
+	}
+	if n.Rows() != size || n.Cols() != size {
+		log.Fatalf("%s: expected %dx%d matrix, got %dx%d", caller, size, size, n.Rows(), n.Cols())
+	}
+	return float32s(n)
+}
+
+// equal32 compares size x size float32 values with a matrix
+func equal32(caller string, m []float32, size int, n Matrix) bool {
+	if n.Kind() != reflect.Float32 {
+		log.Fatalf("%s: kinds %v and %v do not match", caller, reflect.Float32, n.Kind())
+	}
+	if n.Rows() != size || n.Cols() != size {
+		log.Fatalf("%s: dimensions (%d, %d) and (%d, %d) do not match", caller, size, size, n.Rows(), n.Cols())
+	}
+
+	for i, value := range float32s(n) {
+		if value != m[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// index32 provides the position of a value, checking the bounds
+func index32(caller string, size int, row int, col int) int {
+	if row < 0 || row >= size || col < 0 || col >= size {
+		log.Panicf("%s: index (%d, %d) out of bounds, expected(<%d, <%d)", caller, row, col, size, size)
+	}
+	return row*size + col
+}
+
+// value32 checks that a value is a float32
+func value32(caller string, value interface{}) float32 {
+	f, ok := value.(float32)
+	if !ok {
+		log.Panicf("%s: wrong value type %v, expected %v", caller, reflect.TypeOf(value), reflect.Float32)
+	}
+	return f
+}
+
+// string32 formats size x size float32 values like the generic matrix
+func string32(m []float32, size int) string {
+	var sb strings.Builder
+	for r := 0; r < size; r++ {
+		for c := 0; c < size; c++ {
+			sb.WriteString(fmt.Sprintf("%v ", m[r*size+c]))
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
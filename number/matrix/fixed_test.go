@@ -0,0 +1,76 @@
+package matrix
+
+import (
+	"reflect"
+	"testing"
+
+	"../vector"
+)
+
+func Test_FixedFactories(t *testing.T) {
+	if _, ok := NewMatrix([][]float32{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}}).(*Mat3); !ok {
+		t.Errorf("Expected a Mat3 for 3x3 float32 values")
+	}
+	if _, ok := NewMatrix([4][4]float32{}).(*Mat4); !ok {
+		t.Errorf("Expected a Mat4 for 4x4 float32 values")
+	}
+	if _, ok := ZeroMatrix(4, 4, reflect.Float32).(*Mat4); !ok {
+		t.Errorf("Expected a Mat4 for a 4x4 Float32 zero matrix")
+	}
+	if m := UnitMatrix(3, 3, reflect.Float32); !m.Equal(NewMatrix([3][3]float32{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}})) {
+		t.Errorf("Expected the unit matrix, got %v", m)
+	}
+	if _, ok := NewMatrix([][]float32{{1, 0}, {0, 1}}).(genericMatrix); !ok {
+		t.Errorf("Expected a generic matrix for 2x2 float32 values")
+	}
+}
+
+func Test_FixedOperations(t *testing.T) {
+	values := [][]float32{
+		{1.0, 2.0, 0.0, 1.0},
+		{0.0, 1.0, 3.0, -1.0},
+		{2.0, 0.0, 1.0, 0.5},
+		{0.0, 0.0, 0.0, 1.0},
+	}
+	fixed := NewMatrix(values)
+	generic := genericZeroMatrix(4, 4, reflect.Float32)
+	for r, row := range values {
+		for c, value := range row {
+			generic.Set(r, c, value)
+		}
+	}
+	if !fixed.Equal(generic) || !generic.Equal(fixed) || fixed.String() != generic.String() {
+		t.Errorf("Expected %v to equal %v", fixed, generic)
+	}
+
+	// Fixed and generic matrices give the same results and mix freely
+	v := vector.NewVector([]float32{1.0, 2.0, 3.0, 1.0})
+	if !fixed.Mulv(v).Equal(generic.Mulv(v)) || !fixed.Mulv(v).Equal(vector.NewVector([]float32{6.0, 10.0, 5.5, 1.0})) {
+		t.Errorf("Expected %v, got %v", generic.Mulv(v), fixed.Mulv(v))
+	}
+	if !fixed.Mulm(generic).Equal(generic.Mulm(generic)) || !generic.Mulm(fixed).Equal(fixed.Mulm(fixed)) {
+		t.Errorf("Expected %v, got %v", generic.Mulm(generic), fixed.Mulm(generic))
+	}
+	column := NewMatrix([][]float32{{1.0}, {2.0}, {3.0}, {1.0}})
+	if p := fixed.Mulm(column); p.Rows() != 4 || p.Cols() != 1 || p.Get(1, 0).(float32) != 10.0 {
+		t.Errorf("Expected a 4x1 matrix, got %v", p)
+	}
+
+	if !fixed.Transpose().Equal(generic.Transpose()) || fixed.Transpose().Get(0, 2).(float32) != 2.0 {
+		t.Errorf("Expected %v, got %v", generic.Transpose(), fixed.Transpose())
+	}
+	if !fixed.Add(generic).Equal(generic.Add(fixed)) || !fixed.Sub(generic).Equal(ZeroMatrix(4, 4, reflect.Float32)) {
+		t.Errorf("Expected %v, got %v", generic.Add(fixed), fixed.Add(generic))
+	}
+	if !fixed.Hadamard(generic).Equal(generic.Hadamard(fixed)) || !fixed.Muls(float32(2.0)).Equal(generic.Add(generic)) {
+		t.Errorf("Expected %v, got %v", generic.Hadamard(fixed), fixed.Hadamard(generic))
+	}
+	if !fixed.Muls(float32(3.0)).Divs(float32(3.0)).Equal(generic.Muls(float32(3.0)).Divs(float32(3.0))) {
+		t.Errorf("Expected %v, got %v", generic, fixed.Muls(float32(3.0)).Divs(float32(3.0)))
+	}
+
+	fixed.Set(3, 0, float32(7.0))
+	if fixed.Get(3, 0).(float32) != 7.0 {
+		t.Errorf("Expected 7 at (3, 0), got %v", fixed)
+	}
+}
@@ -140,41 +140,51 @@ func genericRandomMatrix(rows int, cols int, kind reflect.Kind) Matrix {
 	return matrix
 }
 
-func genericNewMatrix(values interface{}) Matrix {
+func genericNewMatrix(values interface{}) (Matrix, error) {
+	if values == nil {
+		return nil, &Error{Op: "matrix.NewMatrix", Err: ErrNotArray, Detail: "got nil"}
+	}
 	source := reflect.ValueOf(values)
 	// Check row-level
 	kind := source.Kind()
 	if kind != reflect.Array && kind != reflect.Slice {
-		log.Fatalf("genericNewMatrix: expected an array or slice, got %v", kind)
+		return nil, &Error{Op: "matrix.NewMatrix", Err: ErrNotArray, Detail: fmt.Sprintf("got %v", kind)}
 	}
 	rows := source.Len()
 	if rows == 0 {
-		log.Fatalf("genericNewMatrix: cannot create with zero rows")
+		return nil, &Error{Op: "matrix.NewMatrix", Err: ErrEmpty, Detail: "got zero rows"}
 	}
 	// Check col-level
-	kind = source.Index(0).Kind()
+	kind = source.Type().Elem().Kind()
 	if kind != reflect.Array && kind != reflect.Slice {
-		log.Fatalf("genericNewMatrix: expected an array or slice, got %v", kind)
+		return nil, &Error{Op: "matrix.NewMatrix", Err: ErrNotArray, Detail: fmt.Sprintf("got rows of %v", kind)}
 	}
 	cols := source.Index(0).Len()
 	if cols == 0 {
-		log.Fatalf("genericNewMatrix: cannot create with zero cols")
+		return nil, &Error{Op: "matrix.NewMatrix", Err: ErrEmpty, Detail: "got zero cols"}
 	}
 
-	// Fill with the content
-	kind = source.Index(0).Index(0).Kind()
-	matrix := genericZeroMatrix(rows, cols, kind)
+	// Fill with the content, named types like time.Duration don't fit the cells
+	element := source.Type().Elem().Elem()
+	kind = element.Kind()
+	if !(kind >= reflect.Int && kind <= reflect.Uint64) && kind != reflect.Float32 && kind != reflect.Float64 {
+		return nil, &Error{Op: "matrix.NewMatrix", Err: ErrKind, Detail: fmt.Sprintf("got %v", element)}
+	}
+	matrix := genericZeroMatrix(rows, cols, kind).(genericMatrix)
+	if reflect.TypeOf(matrix.values).Elem() != element {
+		return nil, &Error{Op: "matrix.NewMatrix", Err: ErrKind, Detail: fmt.Sprintf("got %v", element)}
+	}
 	for r := 0; r < rows; r++ {
 		row := source.Index(r)
 		if row.Len() != cols {
-			log.Fatalf("genericFilledMatrix: irregular row %d expected %d cols, got %d", r, cols, row.Len())
+			return nil, &Error{Op: "matrix.NewMatrix", Err: ErrIrregular, Detail: fmt.Sprintf("row %d expected %d cols, got %d", r, cols, row.Len())}
 		}
 		for c := 0; c < cols; c++ {
-			matrix.Set(r, c, source.Index(r).Index(c).Interface())
+			matrix.Set(r, c, row.Index(c).Interface())
 		}
 	}
 
-	return matrix
+	return matrix, nil
 }
 
 func (m genericMatrix) Mulv(v vector.Vector) vector.Vector {
@@ -350,3 +360,103 @@ func (m genericMatrix) String() string {
 	}
 	return sb.String()
 }
+
+// Transpose provides the matrix mirrored along its main diagonal
+func (m genericMatrix) Transpose() Matrix {
+	result := ZeroMatrix(m.cols, m.rows, m.kind)
+	for r := 0; r < m.rows; r++ {
+		for c := 0; c < m.cols; c++ {
+			result.Set(c, r, m.Get(r, c))
+		}
+	}
+	return result
+}
+
+// Add provides the sum of two matrices
+func (m genericMatrix) Add(n Matrix) Matrix {
+	m.compatible("genericMatrix.Add", n)
+	return m.combine(n.Get,
+		func(a, b int64) int64 { return a + b },
+		func(a, b uint64) uint64 { return a + b },
+		func(a, b float64) float64 { return a + b })
+}
+
+// Sub provides the difference of two matrices
+func (m genericMatrix) Sub(n Matrix) Matrix {
+	m.compatible("genericMatrix.Sub", n)
+	return m.combine(n.Get,
+		func(a, b int64) int64 { return a - b },
+		func(a, b uint64) uint64 { return a - b },
+		func(a, b float64) float64 { return a - b })
+}
+
+// Hadamard provides the element by element product of two matrices
+func (m genericMatrix) Hadamard(n Matrix) Matrix {
+	m.compatible("genericMatrix.Hadamard", n)
+	return m.combine(n.Get,
+		func(a, b int64) int64 { return a * b },
+		func(a, b uint64) uint64 { return a * b },
+		func(a, b float64) float64 { return a * b })
+}
+
+// Muls multiplies a matrix by a scalar of the same kind
+func (m genericMatrix) Muls(s interface{}) Matrix {
+	m.scalar("genericMatrix.Muls", s)
+	return m.combine(func(int, int) interface{} { return s },
+		func(a, b int64) int64 { return a * b },
+		func(a, b uint64) uint64 { return a * b },
+		func(a, b float64) float64 { return a * b })
+}
+
+// Divs divides a matrix by a scalar of the same kind
+func (m genericMatrix) Divs(s interface{}) Matrix {
+	m.scalar("genericMatrix.Divs", s)
+	return m.combine(func(int, int) interface{} { return s },
+		func(a, b int64) int64 { return a / b },
+		func(a, b uint64) uint64 { return a / b },
+		func(a, b float64) float64 { return a / b })
+}
+
+// compatible stops on matrices of another kind or size
+func (m genericMatrix) compatible(caller string, n Matrix) {
+	if m.Kind() != n.Kind() {
+		log.Fatalf("%s: expected matrix type %v, got %v", caller, m.Kind(), n.Kind())
+	}
+	if m.Rows() != n.Rows() || m.Cols() != n.Cols() {
+		log.Fatalf("%s: expected %dx%d matrix, got %dx%d", caller, m.Rows(), m.Cols(), n.Rows(), n.Cols())
+	}
+}
+
+// scalar stops on scalars of another kind
+func (m genericMatrix) scalar(caller string, s interface{}) {
+	if reflect.TypeOf(s) != reflect.TypeOf(m.values).Elem() {
+		log.Fatalf("%s: Scalar Type %v doesn't match matrix type %v", caller, reflect.TypeOf(s), m.Kind())
+	}
+}
+
+// combine applies an operation to every element of the matrix and the matching element of other
+// Integers are calculated in 64 bits, converting back keeps the lower bits which is where the
+// smaller integers would have ended up. Floats are calculated in float64, which rounds to the
+// same float32 as plain float32 arithmetic would for a single operation.
+func (m genericMatrix) combine(other func(r int, c int) interface{},
+	signed func(a, b int64) int64, unsigned func(a, b uint64) uint64, float func(a, b float64) float64) Matrix {
+
+	t := reflect.TypeOf(m.values).Elem()
+	result := ZeroMatrix(m.rows, m.cols, m.kind)
+	for r := 0; r < m.rows; r++ {
+		for c := 0; c < m.cols; c++ {
+			a, b := reflect.ValueOf(m.Get(r, c)), reflect.ValueOf(other(r, c))
+			var value reflect.Value
+			switch {
+			case m.kind >= reflect.Int && m.kind <= reflect.Int64:
+				value = reflect.ValueOf(signed(a.Int(), b.Int()))
+			case m.kind >= reflect.Uint && m.kind <= reflect.Uint64:
+				value = reflect.ValueOf(unsigned(a.Uint(), b.Uint()))
+			default:
+				value = reflect.ValueOf(float(a.Float(), b.Float()))
+			}
+			result.Set(r, c, value.Convert(t).Interface())
+		}
+	}
+	return result
+}
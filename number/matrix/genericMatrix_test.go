@@ -1,6 +1,7 @@
 package matrix
 
 import (
+	"errors"
 	"reflect"
 	"testing"
 
@@ -8,7 +9,7 @@ import (
 )
 
 func Test_GenericFilledMatrix(t *testing.T) {
-	m := genericNewMatrix([][]int{
+	m := NewMatrix([][]int{
 		{1, 2},
 		{3, 4},
 	})
@@ -19,7 +20,7 @@ func Test_GenericFilledMatrix(t *testing.T) {
 
 func Test_GenericMulv(t *testing.T) {
 	// Check the mul identity matrix for integers
-	m0 := genericNewMatrix([][]int{
+	m0 := NewMatrix([][]int{
 		{1, 0, 0},
 		{0, 1, 0},
 		{0, 0, 1},
@@ -32,7 +33,7 @@ func Test_GenericMulv(t *testing.T) {
 	}
 
 	// Check the mul selection matrix for integers
-	m1 := genericNewMatrix([][]int{
+	m1 := NewMatrix([][]int{
 		{1, 0, 0},
 		{1, 0, 0},
 		{1, 0, 0},
@@ -43,7 +44,7 @@ func Test_GenericMulv(t *testing.T) {
 	}
 
 	// Check the mul as a simple projection for integers
-	m2 := genericNewMatrix([][]int{
+	m2 := NewMatrix([][]int{
 		{1, 0, 1},
 		{0, 1, 1},
 	})
@@ -91,3 +92,67 @@ func Test_GenericMulm(t *testing.T) {
 	}
 
 }
+
+func Test_GenericTranspose(t *testing.T) {
+	m := NewMatrix([][]int{{1, 2, 3}, {4, 5, 6}})
+	expected := NewMatrix([][]int{{1, 4}, {2, 5}, {3, 6}})
+	if r := m.Transpose(); !r.Equal(expected) {
+		t.Errorf("Expected %v, got %v", expected, r)
+	}
+	if r := m.Transpose().Transpose(); !r.Equal(m) {
+		t.Errorf("Expected %v, got %v", m, r)
+	}
+}
+
+func Test_GenericElementWise(t *testing.T) {
+	// Integers wrap arround like the native type
+	a := NewMatrix([][]int8{{100, -100}, {7, 3}})
+	b := NewMatrix([][]int8{{100, 100}, {2, -3}})
+	tests := []struct {
+		result   Matrix
+		expected Matrix
+	}{
+		{a.Add(b), NewMatrix([][]int8{{-56, 0}, {9, 0}})},
+		{a.Sub(b), NewMatrix([][]int8{{0, 56}, {5, 6}})},
+		{a.Hadamard(b), NewMatrix([][]int8{{16, -16}, {14, -9}})},
+		{a.Muls(int8(2)), NewMatrix([][]int8{{-56, 56}, {14, 6}})},
+		{a.Divs(int8(2)), NewMatrix([][]int8{{50, -50}, {3, 1}})},
+	}
+	for i, test := range tests {
+		if !test.result.Equal(test.expected) {
+			t.Errorf("Test %d: expected %v, got %v", i, test.expected, test.result)
+		}
+	}
+
+	c := NewMatrix([][]float64{{1.0, 2.0, 3.0}, {4.0, 5.0, 6.0}})
+	if r := c.Add(c).Sub(c.Muls(0.5)); !r.Equal(c.Muls(1.5)) {
+		t.Errorf("Expected %v, got %v", c.Muls(1.5), r)
+	}
+	if r := c.Hadamard(c).Divs(2.0); r.Get(1, 2).(float64) != 18.0 {
+		t.Errorf("Expected 18 at (1, 2), got %v", r)
+	}
+}
+
+func Test_TryNewMatrix(t *testing.T) {
+	tests := []struct {
+		values   interface{}
+		expected error
+	}{
+		{nil, ErrNotArray},
+		{[]int{1, 2}, ErrNotArray},
+		{[][]int{}, ErrEmpty},
+		{[][]int{{}}, ErrEmpty},
+		{[][]string{{"a"}}, ErrKind},
+		{[][]int{{1, 2}, {3}}, ErrIrregular},
+	}
+	for i, test := range tests {
+		m, err := TryNewMatrix(test.values)
+		var e *Error
+		if m != nil || !errors.Is(err, test.expected) || !errors.As(err, &e) || e.Op != "matrix.NewMatrix" {
+			t.Errorf("Test %d: expected %v, got %v %v", i, test.expected, m, err)
+		}
+	}
+	if m, err := TryNewMatrix([2][2]float32{{1.0, 0.0}, {0.0, 1.0}}); err != nil || !m.Equal(UnitMatrix(2, 2, reflect.Float32)) {
+		t.Errorf("Expected the unit matrix, got %v %v", m, err)
+	}
+}
@@ -0,0 +1,236 @@
+package matrix
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// LU is the decomposition P * A = L * U of a square Float32 or Float64 matrix A
+// L is lower triangular with ones on the diagonal, U is upper triangular and the permutation
+// P swaps the rows of A so the largest value in every column is used as pivot (partial pivoting).
+// The calculation is done in float64, the matrices it provides have the kind of A.
+type LU struct {
+	kind     reflect.Kind
+	size     int
+	values   []float64 // L below the diagonal, U on and above it, row by row
+	pivot    []int     // row i of P * A is row pivot[i] of A
+	sign     float64   // the determinant of P
+	singular bool
+}
+
+// NewLU decomposes a square Float32 or Float64 matrix
+// Singular matrices have a decomposition too, but no inverse: check with Singular.
+func NewLU(m Matrix) (*LU, error) {
+	return decompose("matrix.NewLU", m)
+}
+
+// Determinant provides the determinant of a square Float32 or Float64 matrix
+func Determinant(m Matrix) (float64, error) {
+	lu, err := decompose("matrix.Determinant", m)
+	if err != nil {
+		return 0.0, err
+	}
+	return lu.Determinant(), nil
+}
+
+// Inverse provides the inverse of a square Float32 or Float64 matrix, ErrSingular if it has none
+func Inverse(m Matrix) (Matrix, error) {
+	lu, err := decompose("matrix.Inverse", m)
+	if err != nil {
+		return nil, err
+	}
+	return lu.inverse("matrix.Inverse")
+}
+
+// decompose does the work for NewLU, reporting errors on behalf of op
+func decompose(op string, m Matrix) (*LU, error) {
+	if m.Kind() != reflect.Float32 && m.Kind() != reflect.Float64 {
+		return nil, &Error{Op: op, Err: ErrKind, Detail: fmt.Sprintf("expects Float32 or Float64, got %v", m.Kind())}
+	}
+	if m.Rows() != m.Cols() {
+		return nil, &Error{Op: op, Err: ErrNotSquare, Detail: fmt.Sprintf("got %dx%d", m.Rows(), m.Cols())}
+	}
+
+	n := m.Rows()
+	values, largest := float64Values(m)
+	lu := &LU{kind: m.Kind(), size: n, values: values, pivot: make([]int, n), sign: 1.0}
+	for r := range lu.pivot {
+		lu.pivot[r] = r
+	}
+
+	// Pivots this much smaller than the values of the matrix are rounding errors on zero
+	tolerance := largest * float64(n) * epsilon(lu.kind)
+
+	a := lu.values
+	for k := 0; k < n; k++ {
+		// Take the largest value in the column as pivot
+		p := k
+		for r := k + 1; r < n; r++ {
+			if math.Abs(a[r*n+k]) > math.Abs(a[p*n+k]) {
+				p = r
+			}
+		}
+		if p != k {
+			for c := 0; c < n; c++ {
+				a[k*n+c], a[p*n+c] = a[p*n+c], a[k*n+c]
+			}
+			lu.pivot[k], lu.pivot[p] = lu.pivot[p], lu.pivot[k]
+			lu.sign = -lu.sign
+		}
+
+		if math.Abs(a[k*n+k]) <= tolerance {
+			lu.singular = true
+			continue
+		}
+		for r := k + 1; r < n; r++ {
+			a[r*n+k] /= a[k*n+k]
+			for c := k + 1; c < n; c++ {
+				a[r*n+c] -= a[r*n+k] * a[k*n+c]
+			}
+		}
+	}
+
+	return lu, nil
+}
+
+// Singular tells if the matrix has no inverse, allowing for rounding errors
+func (lu *LU) Singular() bool {
+	return lu.singular
+}
+
+// Determinant provides the determinant of the matrix, the product of the diagonal of U
+func (lu *LU) Determinant() float64 {
+	det := lu.sign
+	for i := 0; i < lu.size; i++ {
+		det *= lu.values[i*lu.size+i]
+	}
+	return det
+}
+
+// L provides the lower triangular matrix, with ones on the diagonal
+func (lu *LU) L() Matrix {
+	return lu.matrix(func(r int, c int) float64 {
+		switch {
+		case r == c:
+			return 1.0
+		case r > c:
+			return lu.values[r*lu.size+c]
+		}
+		return 0.0
+	})
+}
+
+// U provides the upper triangular matrix
+func (lu *LU) U() Matrix {
+	return lu.matrix(func(r int, c int) float64 {
+		if r <= c {
+			return lu.values[r*lu.size+c]
+		}
+		return 0.0
+	})
+}
+
+// P provides the permutation matrix
+func (lu *LU) P() Matrix {
+	return lu.matrix(func(r int, c int) float64 {
+		if lu.pivot[r] == c {
+			return 1.0
+		}
+		return 0.0
+	})
+}
+
+// Inverse provides the inverse of the matrix, ErrSingular if it has none
+func (lu *LU) Inverse() (Matrix, error) {
+	return lu.inverse("LU.Inverse")
+}
+
+// inverse solves for every column of the unit matrix, reporting errors on behalf of op
+func (lu *LU) inverse(op string) (Matrix, error) {
+	if lu.singular {
+		return nil, &Error{Op: op, Err: ErrSingular}
+	}
+
+	columns := make([][]float64, lu.size)
+	for c := range columns {
+		unit := make([]float64, lu.size)
+		unit[c] = 1.0
+		columns[c] = lu.solve(unit)
+	}
+	return lu.matrix(func(r int, c int) float64 {
+		return columns[c][r]
+	}), nil
+}
+
+// solve provides x for A * x = b with forward and back substitution, A must not be singular
+func (lu *LU) solve(b []float64) []float64 {
+	n, a := lu.size, lu.values
+	x := make([]float64, n)
+	for r := 0; r < n; r++ {
+		x[r] = b[lu.pivot[r]]
+		for c := 0; c < r; c++ {
+			x[r] -= a[r*n+c] * x[c]
+		}
+	}
+	for r := n - 1; r >= 0; r-- {
+		for c := r + 1; c < n; c++ {
+			x[r] -= a[r*n+c] * x[c]
+		}
+		x[r] /= a[r*n+r]
+	}
+	return x
+}
+
+// matrix builds a matrix of the kind of the decomposition
+func (lu *LU) matrix(value func(r int, c int) float64) Matrix {
+	return float64Matrix(lu.size, lu.size, lu.kind, value)
+}
+
+// float64Values provides the values of a Float32 or Float64 matrix row by row, and the largest absolute value
+func float64Values(m Matrix) ([]float64, float64) {
+	values := make([]float64, m.Rows()*m.Cols())
+	largest := 0.0
+	for r := 0; r < m.Rows(); r++ {
+		for c := 0; c < m.Cols(); c++ {
+			values[r*m.Cols()+c] = toFloat64(m.Get(r, c))
+			largest = math.Max(largest, math.Abs(values[r*m.Cols()+c]))
+		}
+	}
+	return values, largest
+}
+
+// float64Matrix builds a Float32 or Float64 matrix from float64 values
+func float64Matrix(rows int, cols int, kind reflect.Kind, value func(r int, c int) float64) Matrix {
+	m := ZeroMatrix(rows, cols, kind)
+	for r := 0; r < rows; r++ {
+		for c := 0; c < cols; c++ {
+			m.Set(r, c, fromFloat64(value(r, c), kind))
+		}
+	}
+	return m
+}
+
+// epsilon provides the distance between 1 and the next larger value of a Float32 or Float64
+func epsilon(kind reflect.Kind) float64 {
+	if kind == reflect.Float32 {
+		return 0x1p-23
+	}
+	return 0x1p-52
+}
+
+// toFloat64 widens a Float32 or Float64 value
+func toFloat64(value interface{}) float64 {
+	if f, ok := value.(float32); ok {
+		return float64(f)
+	}
+	return value.(float64)
+}
+
+// fromFloat64 narrows a value to Float32 or Float64
+func fromFloat64(f float64, kind reflect.Kind) interface{} {
+	if kind == reflect.Float32 {
+		return float32(f)
+	}
+	return f
+}
@@ -0,0 +1,77 @@
+package matrix
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	"../vector"
+)
+
+// nearMatrix compares two Float64 matrices allowing for rounding errors
+func nearMatrix(m Matrix, n Matrix, tolerance float64) bool {
+	for r := 0; r < m.Rows(); r++ {
+		for c := 0; c < m.Cols(); c++ {
+			if math.Abs(toFloat64(m.Get(r, c))-toFloat64(n.Get(r, c))) > tolerance {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func Test_LU(t *testing.T) {
+	a := NewMatrix([][]float64{
+		{0.0, 2.0, 1.0},
+		{1.0, 1.0, 0.0},
+		{4.0, -2.0, 3.0},
+	})
+	lu, err := NewLU(a)
+	if err != nil || lu.Singular() {
+		t.Fatalf("Expected a decomposition, got %v", err)
+	}
+	if !nearMatrix(lu.P().Mulm(a), lu.L().Mulm(lu.U()), 1e-12) {
+		t.Errorf("Expected P*A = L*U, got\n%v and\n%v", lu.P().Mulm(a), lu.L().Mulm(lu.U()))
+	}
+
+	// The largest value in the first column is the first pivot
+	if lu.U().Get(0, 0).(float64) != 4.0 {
+		t.Errorf("Expected pivot 4, got %v", lu.U())
+	}
+	if det, _ := Determinant(a); math.Abs(det+12.0) > 1e-12 {
+		t.Errorf("Expected determinant -12, got %f", det)
+	}
+}
+
+func Test_Inverse(t *testing.T) {
+	// A Float32 transformation comes back as a Mat4 undoing it
+	m := Translation(vector.NewVector([]float32{1.0, 2.0, 3.0})).Mulm(RotationZ(30.0)).Mulm(Scale(vector.NewVector([]float32{2.0, 1.0, 0.5})))
+	inverse, err := Inverse(m)
+	if err != nil {
+		t.Fatalf("Expected an inverse, got %v", err)
+	}
+	if _, ok := inverse.(*Mat4); !ok || !nearMatrix(inverse.Mulm(m), UnitMatrix(4, 4, reflect.Float32), 1e-6) {
+		t.Errorf("Expected the inverse as Mat4, got %v", inverse)
+	}
+
+	// Singular matrices have no inverse, also when rounding hides it
+	singular := NewMatrix([][]float32{
+		{1.0, 2.0, 3.0},
+		{4.0, 5.0, 6.0},
+		{7.0, 8.0, 9.0},
+	})
+	_, err = Inverse(singular)
+	var e *Error
+	if !errors.Is(err, ErrSingular) || !errors.As(err, &e) || e.Op != "matrix.Inverse" {
+		t.Errorf("Expected %v, got %v", ErrSingular, err)
+	}
+
+	// Only square float matrices
+	if _, err := Inverse(NewMatrix([][]float64{{1.0, 2.0}})); !errors.Is(err, ErrNotSquare) {
+		t.Errorf("Expected %v, got %v", ErrNotSquare, err)
+	}
+	if _, err := Determinant(NewMatrix([][]int{{1}})); !errors.Is(err, ErrKind) {
+		t.Errorf("Expected %v, got %v", ErrKind, err)
+	}
+}
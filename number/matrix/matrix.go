@@ -2,6 +2,7 @@ package matrix
 
 import (
 	"fmt"
+	"log"
 	"reflect"
 
 	"../vector"
@@ -11,6 +12,12 @@ import (
 type Matrix interface {
 	Mulv(v vector.Vector) vector.Vector
 	Mulm(n Matrix) Matrix
+	Transpose() Matrix
+	Add(n Matrix) Matrix
+	Sub(n Matrix) Matrix
+	Muls(s interface{}) Matrix
+	Divs(s interface{}) Matrix
+	Hadamard(n Matrix) Matrix
 	Kind() reflect.Kind
 	Rows() int
 	Cols() int
@@ -22,15 +29,48 @@ type Matrix interface {
 
 // ZeroMatrix creates a matrix 'rows' high and 'cols' wide
 func ZeroMatrix(rows int, cols int, kind reflect.Kind) Matrix {
+	if kind == reflect.Float32 && rows == cols {
+		if m := fixedMatrix(rows, nil); m != nil {
+			return m
+		}
+	}
 	return genericZeroMatrix(rows, cols, kind)
 }
 
 // UnitMatrix creates a matrix with all ones across the main diagonal
 func UnitMatrix(rows int, cols int, kind reflect.Kind) Matrix {
+	if kind == reflect.Float32 && rows == cols {
+		if m := fixedMatrix(rows, nil); m != nil {
+			for i := 0; i < rows; i++ {
+				m.Set(i, i, float32(1.0))
+			}
+			return m
+		}
+	}
 	return genericUnitMatrix(rows, cols, kind)
 }
 
 // NewMatrix creates a matrix based on a number of values
 func NewMatrix(values interface{}) Matrix {
+	m, err := TryNewMatrix(values)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return m
+}
+
+// TryNewMatrix creates a matrix based on a number of values, like NewMatrix, but reports
+// a *Error instead of stopping when the values don't make a matrix
+func TryNewMatrix(values interface{}) (Matrix, error) {
+	switch rows := values.(type) {
+	case [][]float32:
+		if m := fixedRows(rows); m != nil {
+			return m, nil
+		}
+	case [3][3]float32:
+		return fixedRows([][]float32{rows[0][:], rows[1][:], rows[2][:]}), nil
+	case [4][4]float32:
+		return fixedRows([][]float32{rows[0][:], rows[1][:], rows[2][:], rows[3][:]}), nil
+	}
 	return genericNewMatrix(values)
 }
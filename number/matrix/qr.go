@@ -0,0 +1,143 @@
+package matrix
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// QR is the decomposition A = Q * R of a Float32 or Float64 matrix A with at least as many rows as columns
+// Q has the size of A and orthonormal columns, R is square and upper triangular. The calculation is
+// done in float64 with Householder reflections, the matrices it provides have the kind of A.
+// Orthonormalizing the columns of A, like those of a rotation matrix drifting from rounding errors,
+// takes Q, as long as A has full rank.
+type QR struct {
+	kind      reflect.Kind
+	rows      int
+	cols      int
+	values    []float64 // the reflections on and below the diagonal, R above it, row by row
+	diagonal  []float64 // the diagonal of R
+	dependent int       // the first column that depends on the ones before it, -1 if none
+}
+
+// NewQR decomposes a Float32 or Float64 matrix with at least as many rows as columns
+// Matrices without full rank have a decomposition too, check with FullRank.
+func NewQR(m Matrix) (*QR, error) {
+	if m.Kind() != reflect.Float32 && m.Kind() != reflect.Float64 {
+		return nil, &Error{Op: "matrix.NewQR", Err: ErrKind, Detail: fmt.Sprintf("expects Float32 or Float64, got %v", m.Kind())}
+	}
+	if m.Rows() < m.Cols() {
+		return nil, &Error{Op: "matrix.NewQR", Err: ErrDimension, Detail: fmt.Sprintf("expects at least as many rows as columns, got %dx%d", m.Rows(), m.Cols())}
+	}
+	return decomposeQR(m), nil
+}
+
+// decomposeQR does the work for NewQR, the matrix has been checked
+func decomposeQR(m Matrix) *QR {
+	rows, cols := m.Rows(), m.Cols()
+	values, largest := float64Values(m)
+	qr := &QR{m.Kind(), rows, cols, values, make([]float64, cols), -1}
+
+	// Columns this much shorter than the values of the matrix are rounding errors on zero
+	tolerance := largest * float64(rows) * epsilon(m.Kind())
+
+	a := qr.values
+	for k := 0; k < cols; k++ {
+		// What is left of the column below the diagonal, after taking out the columns before it
+		norm := 0.0
+		for r := k; r < rows; r++ {
+			norm = math.Hypot(norm, a[r*cols+k])
+		}
+		if norm <= tolerance {
+			if qr.dependent < 0 {
+				qr.dependent = k
+			}
+			continue
+		}
+
+		// Reflect the column onto the diagonal, the sign avoids cancellation
+		if a[k*cols+k] < 0.0 {
+			norm = -norm
+		}
+		for r := k; r < rows; r++ {
+			a[r*cols+k] /= norm
+		}
+		a[k*cols+k] += 1.0
+
+		// And reflect the remaining columns with it
+		for c := k + 1; c < cols; c++ {
+			qr.reflect(k, a[c:], cols)
+		}
+		qr.diagonal[k] = -norm
+	}
+
+	return qr
+}
+
+// FullRank tells if no column depends on the others, allowing for rounding errors
+func (qr *QR) FullRank() bool {
+	return qr.dependent < 0
+}
+
+// Q provides the matrix with orthonormal columns
+func (qr *QR) Q() Matrix {
+	columns := make([][]float64, qr.cols)
+	for c := range columns {
+		column := make([]float64, qr.rows)
+		column[c] = 1.0
+		for k := qr.cols - 1; k >= 0; k-- {
+			if qr.diagonal[k] != 0.0 {
+				qr.reflect(k, column, 1)
+			}
+		}
+		columns[c] = column
+	}
+	return float64Matrix(qr.rows, qr.cols, qr.kind, func(r int, c int) float64 {
+		return columns[c][r]
+	})
+}
+
+// R provides the upper triangular matrix
+func (qr *QR) R() Matrix {
+	return float64Matrix(qr.cols, qr.cols, qr.kind, func(r int, c int) float64 {
+		switch {
+		case r == c:
+			return qr.diagonal[r]
+		case r < c:
+			return qr.values[r*qr.cols+c]
+		}
+		return 0.0
+	})
+}
+
+// reflect applies reflection k to every stride'th value of v, starting at row k
+func (qr *QR) reflect(k int, v []float64, stride int) {
+	a, cols := qr.values, qr.cols
+	s := 0.0
+	for r := k; r < qr.rows; r++ {
+		s += a[r*cols+k] * v[r*stride]
+	}
+	s = -s / a[k*cols+k]
+	for r := k; r < qr.rows; r++ {
+		v[r*stride] += s * a[r*cols+k]
+	}
+}
+
+// solve provides the x that minimizes |A * x - b|, A must have full rank
+func (qr *QR) solve(b []float64) []float64 {
+	y := append([]float64{}, b...)
+	for k := 0; k < qr.cols; k++ {
+		qr.reflect(k, y, 1)
+	}
+
+	// Back substitution with R, Q^T * b has the distance to b below the first cols rows
+	a, cols := qr.values, qr.cols
+	x := y[:cols]
+	for r := cols - 1; r >= 0; r-- {
+		for c := r + 1; c < cols; c++ {
+			x[r] -= a[r*cols+c] * x[c]
+		}
+		x[r] /= qr.diagonal[r]
+	}
+	return x
+}
@@ -0,0 +1,60 @@
+package matrix
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func Test_QR(t *testing.T) {
+	a := NewMatrix([][]float64{
+		{12.0, -51.0, 4.0},
+		{6.0, 167.0, -68.0},
+		{-4.0, 24.0, -41.0},
+		{1.0, 2.0, 3.0},
+	})
+	qr, err := NewQR(a)
+	if err != nil || !qr.FullRank() {
+		t.Fatalf("Expected a decomposition with full rank, got %v", err)
+	}
+	q, r := qr.Q(), qr.R()
+	if q.Rows() != 4 || q.Cols() != 3 || r.Rows() != 3 || r.Cols() != 3 {
+		t.Fatalf("Expected a 4x3 Q and 3x3 R, got\n%v and\n%v", q, r)
+	}
+	if !nearMatrix(q.Mulm(r), a, 1e-12) {
+		t.Errorf("Expected Q*R = A, got\n%v", q.Mulm(r))
+	}
+	if !nearMatrix(q.Transpose().Mulm(q), UnitMatrix(3, 3, reflect.Float64), 1e-12) {
+		t.Errorf("Expected orthonormal columns, got\n%v", q)
+	}
+	if r.Get(2, 0).(float64) != 0.0 || r.Get(1, 0).(float64) != 0.0 || r.Get(2, 1).(float64) != 0.0 {
+		t.Errorf("Expected R to be upper triangular, got\n%v", r)
+	}
+
+	// Orthonormalizing a rotation matrix that drifted
+	rotation := Linear(RotationZ(30.0)).Mulm(Linear(RotationX(45.0))).Add(NewMatrix([][]float32{
+		{0.002, 0.0, -0.001},
+		{0.0, 0.001, 0.0},
+		{0.001, 0.0, 0.0},
+	}))
+	qr, err = NewQR(rotation)
+	if err != nil {
+		t.Fatalf("Expected a decomposition, got %v", err)
+	}
+	if q := qr.Q(); q.Kind() != reflect.Float32 || !nearMatrix(q.Transpose().Mulm(q), UnitMatrix(3, 3, reflect.Float32), 1e-6) {
+		t.Errorf("Expected a Float32 rotation, got\n%v", q)
+	}
+
+	// The middle column is twice the first
+	qr, err = NewQR(NewMatrix([][]float64{{1.0, 2.0, 0.0}, {2.0, 4.0, 1.0}, {3.0, 6.0, 5.0}}))
+	if err != nil || qr.FullRank() {
+		t.Errorf("Expected a decomposition without full rank, got %v", err)
+	}
+
+	if _, err := NewQR(NewMatrix([][]float64{{1.0, 2.0}})); !errors.Is(err, ErrDimension) {
+		t.Errorf("Expected %v, got %v", ErrDimension, err)
+	}
+	if _, err := NewQR(NewMatrix([][]int{{1}})); !errors.Is(err, ErrKind) {
+		t.Errorf("Expected %v, got %v", ErrKind, err)
+	}
+}
@@ -0,0 +1,76 @@
+package matrix
+
+import (
+	"fmt"
+	"reflect"
+
+	"../vector"
+)
+
+// Solve provides x for A * x = b, with A a square Float32 or Float64 matrix and b a vector of the same kind
+// It reports ErrSingular when the system has no unique solution.
+func Solve(a Matrix, b vector.Vector) (vector.Vector, error) {
+	lu, err := decompose("matrix.Solve", a)
+	if err != nil {
+		return nil, err
+	}
+	values, err := float64s("matrix.Solve", a, b)
+	if err != nil {
+		return nil, err
+	}
+	if lu.singular {
+		return nil, &Error{Op: "matrix.Solve", Err: ErrSingular}
+	}
+	return newVector(lu.solve(values), a.Kind()), nil
+}
+
+// LeastSquares provides the x that brings A * x closest to b, for a Float32 or Float64 matrix A
+// with at least as many rows as columns and b a vector of the same kind. With more rows than columns
+// there are more equations than unknowns, x minimizes the euclidian length of A * x - b.
+// Every unknown needs to be determined: ErrRankDeficient reports a column that depends on the ones before it.
+func LeastSquares(a Matrix, b vector.Vector) (vector.Vector, error) {
+	if a.Kind() != reflect.Float32 && a.Kind() != reflect.Float64 {
+		return nil, &Error{Op: "matrix.LeastSquares", Err: ErrKind, Detail: fmt.Sprintf("expects Float32 or Float64, got %v", a.Kind())}
+	}
+	values, err := float64s("matrix.LeastSquares", a, b)
+	if err != nil {
+		return nil, err
+	}
+	if a.Rows() < a.Cols() {
+		return nil, &Error{Op: "matrix.LeastSquares", Err: ErrRankDeficient, Detail: fmt.Sprintf("%d equations for %d unknowns", a.Rows(), a.Cols())}
+	}
+
+	qr := decomposeQR(a)
+	if qr.dependent >= 0 {
+		return nil, &Error{Op: "matrix.LeastSquares", Err: ErrRankDeficient, Detail: fmt.Sprintf("column %d", qr.dependent)}
+	}
+	return newVector(qr.solve(values), a.Kind()), nil
+}
+
+// float64s checks that b goes with A and provides its values, reporting errors on behalf of op
+func float64s(op string, a Matrix, b vector.Vector) ([]float64, error) {
+	if b == nil || b.Len() != a.Rows() {
+		return nil, &Error{Op: op, Err: ErrDimension, Detail: fmt.Sprintf("expected vector length %d, got %v", a.Rows(), b)}
+	}
+	if b.Kind() != a.Kind() {
+		return nil, &Error{Op: op, Err: ErrKind, Detail: fmt.Sprintf("expected vector type %v, got %v", a.Kind(), b.Kind())}
+	}
+
+	values := make([]float64, b.Len())
+	for i := range values {
+		values[i] = toFloat64(b.Get(i))
+	}
+	return values, nil
+}
+
+// newVector builds a vector of kind Float32 or Float64
+func newVector(values []float64, kind reflect.Kind) vector.Vector {
+	if kind == reflect.Float64 {
+		return vector.NewVector(values)
+	}
+	narrow := make([]float32, len(values))
+	for i, f := range values {
+		narrow[i] = float32(f)
+	}
+	return vector.NewVector(narrow)
+}
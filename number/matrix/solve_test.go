@@ -0,0 +1,108 @@
+package matrix
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	"../vector"
+)
+
+// nearVector compares two vectors allowing for rounding errors
+func nearVector(v vector.Vector, w vector.Vector, tolerance float64) bool {
+	return v.Len() == w.Len() && v.Sub(w).Abs() <= tolerance
+}
+
+func Test_Solve(t *testing.T) {
+	a := NewMatrix([][]float64{
+		{0.0, 2.0, 1.0},
+		{1.0, 1.0, 0.0},
+		{4.0, -2.0, 3.0},
+	})
+	x := vector.NewVector([]float64{1.0, -1.0, 2.0})
+	if r, err := Solve(a, a.Mulv(x)); err != nil || !nearVector(r, x, 1e-12) {
+		t.Errorf("Expected %v, got %v %v", x, r, err)
+	}
+
+	// Float32 systems give Float32 solutions
+	a32 := NewMatrix([][]float32{{2.0, 1.0}, {1.0, 3.0}})
+	if r, err := Solve(a32, vector.NewVector([]float32{3.0, 4.0})); err != nil || !nearVector(r, vector.NewVector([]float32{1.0, 1.0}), 1e-6) {
+		t.Errorf("Expected [1, 1], got %v %v", r, err)
+	}
+
+	tests := []struct {
+		a        Matrix
+		b        vector.Vector
+		expected error
+	}{
+		{NewMatrix([][]float64{{1.0, 2.0}, {2.0, 4.0}}), vector.NewVector([]float64{1.0, 2.0}), ErrSingular},
+		{NewMatrix([][]float64{{1.0, 2.0, 3.0}}), vector.NewVector([]float64{1.0}), ErrNotSquare},
+		{a, vector.NewVector([]float64{1.0, 2.0}), ErrDimension},
+		{a, nil, ErrDimension},
+		{a, vector.NewVector([]float32{1.0, 2.0, 3.0}), ErrKind},
+		{NewMatrix([][]int{{1}}), vector.NewVector([]int{1}), ErrKind},
+	}
+	for i, test := range tests {
+		r, err := Solve(test.a, test.b)
+		var e *Error
+		if r != nil || !errors.Is(err, test.expected) || !errors.As(err, &e) || e.Op != "matrix.Solve" {
+			t.Errorf("Test %d: expected %v, got %v %v", i, test.expected, r, err)
+		}
+	}
+}
+
+func Test_LeastSquares(t *testing.T) {
+	// Fit the plane z = 2x - y + 3 through points with some noise that cancels out
+	points := [][3]float64{{0, 0, 3.1}, {1, 0, 4.9}, {0, 1, 2.1}, {1, 1, 3.9}, {2, 1, 6.0}, {1, 2, 3.0}}
+	a := ZeroMatrix(len(points), 3, reflect.Float64)
+	b := vector.ZeroVector(len(points), reflect.Float64)
+	for i, p := range points {
+		a.Set(i, 0, p[0])
+		a.Set(i, 1, p[1])
+		a.Set(i, 2, 1.0)
+		b.Set(i, p[2])
+	}
+	x, err := LeastSquares(a, b)
+	if err != nil {
+		t.Fatalf("Expected a fit, got %v", err)
+	}
+
+	// The residual is perpendicular to the columns, the normal equations hold
+	residual := a.Mulv(x).Sub(b)
+	if normal := a.Transpose().Mulv(residual); normal.Abs() > 1e-12 {
+		t.Errorf("Expected A^T * (A * x - b) = 0, got %v", normal)
+	}
+	if math.Abs(x.Get(0).(float64)-2.0) > 0.1 || math.Abs(x.Get(1).(float64)+1.0) > 0.1 || math.Abs(x.Get(2).(float64)-3.0) > 0.1 {
+		t.Errorf("Expected about [2, -1, 3], got %v", x)
+	}
+
+	// Square systems have the exact solution
+	square := NewMatrix([][]float32{{2.0, 1.0}, {1.0, 3.0}})
+	if r, err := LeastSquares(square, vector.NewVector([]float32{3.0, 4.0})); err != nil || !nearVector(r, vector.NewVector([]float32{1.0, 1.0}), 1e-6) {
+		t.Errorf("Expected [1, 1], got %v %v", r, err)
+	}
+
+	// The last column is the sum of the first two
+	dependent := NewMatrix([][]float64{{1.0, 0.0, 1.0}, {0.0, 1.0, 1.0}, {1.0, 1.0, 2.0}, {2.0, 1.0, 3.0}})
+	tests := []struct {
+		a        Matrix
+		b        vector.Vector
+		expected error
+	}{
+		{dependent, vector.NewVector([]float64{1.0, 2.0, 3.0, 4.0}), ErrRankDeficient},
+		{NewMatrix([][]float64{{1.0, 2.0, 3.0}}), vector.NewVector([]float64{1.0}), ErrRankDeficient},
+		{dependent, vector.NewVector([]float64{1.0, 2.0}), ErrDimension},
+		{dependent, vector.NewVector([]float32{1.0, 2.0, 3.0, 4.0}), ErrKind},
+	}
+	for i, test := range tests {
+		r, err := LeastSquares(test.a, test.b)
+		var e *Error
+		if r != nil || !errors.Is(err, test.expected) || !errors.As(err, &e) || e.Op != "matrix.LeastSquares" {
+			t.Errorf("Test %d: expected %v, got %v %v", i, test.expected, r, err)
+		}
+	}
+	if _, err := LeastSquares(dependent, vector.NewVector([]float64{1.0, 2.0, 3.0, 4.0})); err.Error() != "matrix.LeastSquares: columns of the matrix depend on each other, column 2" {
+		t.Errorf("Expected column 2 to be reported, got %v", err)
+	}
+}
@@ -0,0 +1,199 @@
+package matrix
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+
+	"../vector"
+)
+
+// SVD is the singular value decomposition A = U * S * V^T of a Float32 or Float64 matrix A
+// For an m x n matrix with k = min(m, n), U is m x k and V is n x k, both with orthonormal columns,
+// and S is the k x k diagonal matrix of singular values, largest first. The calculation is done in
+// float64 with one-sided Jacobi rotations, the results have the kind of A.
+// The rotation closest to a drifting rotation matrix is U * V^T.
+type SVD struct {
+	kind   reflect.Kind
+	rows   int
+	cols   int
+	u      []float64 // rows x k, row by row
+	values []float64 // the singular values, largest first
+	v      []float64 // cols x k, row by row
+}
+
+// maxSweeps bounds the rounds of Jacobi rotations, they converge long before
+const maxSweeps = 64
+
+// NewSVD decomposes a Float32 or Float64 matrix
+func NewSVD(m Matrix) (*SVD, error) {
+	if m.Kind() != reflect.Float32 && m.Kind() != reflect.Float64 {
+		return nil, &Error{Op: "matrix.NewSVD", Err: ErrKind, Detail: fmt.Sprintf("expects Float32 or Float64, got %v", m.Kind())}
+	}
+
+	// The rotations work on the columns, so a wide matrix is decomposed as its transpose
+	if m.Rows() < m.Cols() {
+		svd, err := NewSVD(m.Transpose())
+		if err != nil {
+			return nil, err
+		}
+		svd.rows, svd.cols, svd.u, svd.v = svd.cols, svd.rows, svd.v, svd.u
+		return svd, nil
+	}
+
+	rows, cols := m.Rows(), m.Cols()
+	u, _ := float64Values(m)
+	v := make([]float64, cols*cols)
+	for i := 0; i < cols; i++ {
+		v[i*cols+i] = 1.0
+	}
+
+	// Rotate pairs of columns until they are all perpendicular, V collects the rotations
+	for sweep := 0; sweep < maxSweeps; sweep++ {
+		rotated := false
+		for p := 0; p < cols-1; p++ {
+			for q := p + 1; q < cols; q++ {
+				alpha, beta, gamma := 0.0, 0.0, 0.0
+				for r := 0; r < rows; r++ {
+					alpha += u[r*cols+p] * u[r*cols+p]
+					beta += u[r*cols+q] * u[r*cols+q]
+					gamma += u[r*cols+p] * u[r*cols+q]
+				}
+				if math.Abs(gamma) <= float64(rows)*epsilon(reflect.Float64)*math.Sqrt(alpha*beta) {
+					continue
+				}
+
+				rotated = true
+				zeta := (beta - alpha) / (2.0 * gamma)
+				t := math.Copysign(1.0, zeta) / (math.Abs(zeta) + math.Hypot(1.0, zeta))
+				c := 1.0 / math.Hypot(1.0, t)
+				rotate(u, rows, cols, p, q, c, c*t)
+				rotate(v, cols, cols, p, q, c, c*t)
+			}
+		}
+		if !rotated {
+			break
+		}
+	}
+
+	// The lengths of the columns are the singular values, normalizing them gives U
+	values := make([]float64, cols)
+	for c := range values {
+		for r := 0; r < rows; r++ {
+			values[c] = math.Hypot(values[c], u[r*cols+c])
+		}
+	}
+	order := make([]int, cols)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i int, j int) bool {
+		return values[order[i]] > values[order[j]]
+	})
+
+	// Columns this much shorter than the longest are rounding errors on zero, they don't give a direction
+	tolerance := values[order[0]] * float64(rows) * epsilon(reflect.Float64)
+
+	svd := &SVD{m.Kind(), rows, cols, make([]float64, rows*cols), make([]float64, cols), make([]float64, cols*cols)}
+	zero := make([]bool, cols)
+	for i, c := range order {
+		svd.values[i] = values[c]
+		zero[i] = values[c] <= tolerance
+		for r := 0; r < rows; r++ {
+			if !zero[i] {
+				svd.u[r*cols+i] = u[r*cols+c] / values[c]
+			}
+		}
+		for r := 0; r < cols; r++ {
+			svd.v[r*cols+i] = v[r*cols+c]
+		}
+	}
+	svd.complete(zero)
+
+	return svd, nil
+}
+
+// U provides the matrix with the left singular vectors as columns
+func (svd *SVD) U() Matrix {
+	k := len(svd.values)
+	return float64Matrix(svd.rows, k, svd.kind, func(r int, c int) float64 {
+		return svd.u[r*k+c]
+	})
+}
+
+// Values provides the singular values, largest first
+func (svd *SVD) Values() vector.Vector {
+	return newVector(svd.values, svd.kind)
+}
+
+// V provides the matrix with the right singular vectors as columns
+func (svd *SVD) V() Matrix {
+	k := len(svd.values)
+	return float64Matrix(svd.cols, k, svd.kind, func(r int, c int) float64 {
+		return svd.v[r*k+c]
+	})
+}
+
+// Rank provides the number of singular values that are not rounding errors on zero
+func (svd *SVD) Rank() int {
+	if len(svd.values) == 0 {
+		return 0
+	}
+	tolerance := svd.values[0] * float64(max(svd.rows, svd.cols)) * epsilon(svd.kind)
+	rank := 0
+	for _, value := range svd.values {
+		if value > tolerance {
+			rank++
+		}
+	}
+	return rank
+}
+
+// complete fills the zero columns of U with unit vectors perpendicular to the others
+func (svd *SVD) complete(zero []bool) {
+	// U has the most rows, the decomposition of a wide matrix swaps it with V later
+	k := len(svd.values)
+	for c := range svd.values {
+		if !zero[c] {
+			continue
+		}
+		for axis := 0; axis < svd.rows; axis++ {
+			column := make([]float64, svd.rows)
+			column[axis] = 1.0
+			for other := 0; other < k; other++ {
+				if other == c {
+					continue
+				}
+				dot := 0.0
+				for r := range column {
+					dot += column[r] * svd.u[r*k+other]
+				}
+				for r := range column {
+					column[r] -= dot * svd.u[r*k+other]
+				}
+			}
+
+			// The axis is not (almost) in the span of the other columns
+			length := 0.0
+			for _, f := range column {
+				length = math.Hypot(length, f)
+			}
+			if length > 0.5 {
+				for r, f := range column {
+					svd.u[r*k+c] = f / length
+				}
+				break
+			}
+		}
+	}
+}
+
+// rotate turns columns p and q of rows x cols values by the angle with cosine c and sine s
+func rotate(a []float64, rows int, cols int, p int, q int, c float64, s float64) {
+	for r := 0; r < rows; r++ {
+		ap, aq := a[r*cols+p], a[r*cols+q]
+		a[r*cols+p] = c*ap - s*aq
+		a[r*cols+q] = s*ap + c*aq
+	}
+}
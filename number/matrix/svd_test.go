@@ -0,0 +1,79 @@
+package matrix
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+// diagonal builds a square Float64 matrix with values on the diagonal
+func diagonal(values []float64) Matrix {
+	d := ZeroMatrix(len(values), len(values), reflect.Float64)
+	for i, value := range values {
+		d.Set(i, i, value)
+	}
+	return d
+}
+
+func Test_SVD(t *testing.T) {
+	tests := []struct {
+		a    Matrix
+		rank int
+	}{
+		{NewMatrix([][]float64{{3.0, 2.0, 2.0}, {2.0, 3.0, -2.0}}), 2},
+		{NewMatrix([][]float64{{1.0, 2.0}, {3.0, 4.0}, {5.0, 6.0}, {7.0, 8.0}}), 2},
+		{NewMatrix([][]float64{{1.0, 2.0, 3.0}, {2.0, 4.0, 6.0}, {1.0, 0.0, 1.0}}), 2},
+		{NewMatrix([][]float64{{1.0, 1.0}, {1.0, 1.0}}), 1},
+		{ZeroMatrix(3, 2, reflect.Float64), 0},
+	}
+	for i, test := range tests {
+		svd, err := NewSVD(test.a)
+		if err != nil {
+			t.Fatalf("Test %d: expected a decomposition, got %v", i, err)
+		}
+		u, s, v := svd.U(), svd.Values(), svd.V()
+		k := min(test.a.Rows(), test.a.Cols())
+		if u.Rows() != test.a.Rows() || u.Cols() != k || s.Len() != k || v.Rows() != test.a.Cols() || v.Cols() != k {
+			t.Errorf("Test %d: expected %dx%d U, %d values and %dx%d V, got\n%v%v\n%v", i, test.a.Rows(), k, k, test.a.Cols(), k, u, s, v)
+			continue
+		}
+
+		values := make([]float64, k)
+		for j := range values {
+			values[j] = s.Get(j).(float64)
+			if j > 0 && values[j] > values[j-1] {
+				t.Errorf("Test %d: expected the largest value first, got %v", i, s)
+			}
+		}
+		if !nearMatrix(u.Mulm(diagonal(values)).Mulm(v.Transpose()), test.a, 1e-12) {
+			t.Errorf("Test %d: expected U*S*V^T = A, got\n%v", i, u.Mulm(diagonal(values)).Mulm(v.Transpose()))
+		}
+		if !nearMatrix(u.Transpose().Mulm(u), UnitMatrix(k, k, reflect.Float64), 1e-12) || !nearMatrix(v.Transpose().Mulm(v), UnitMatrix(k, k, reflect.Float64), 1e-12) {
+			t.Errorf("Test %d: expected orthonormal columns, got\n%v\n%v", i, u, v)
+		}
+		if svd.Rank() != test.rank {
+			t.Errorf("Test %d: expected rank %d, got %d", i, test.rank, svd.Rank())
+		}
+	}
+
+	// Singular values of a 2x3 example known by hand
+	svd, _ := NewSVD(tests[0].a)
+	if s := svd.Values(); math.Abs(s.Get(0).(float64)-5.0) > 1e-12 || math.Abs(s.Get(1).(float64)-3.0) > 1e-12 {
+		t.Errorf("Expected [5, 3], got %v", s)
+	}
+
+	// The rotation closest to a drifting one is U * V^T
+	rotation := Linear(RotationY(60.0))
+	svd, err := NewSVD(rotation.Add(rotation.Muls(float32(0.01))))
+	if err != nil {
+		t.Fatalf("Expected a decomposition, got %v", err)
+	}
+	if r := svd.U().Mulm(svd.V().Transpose()); r.Kind() != reflect.Float32 || !nearMatrix(r, rotation, 1e-6) {
+		t.Errorf("Expected\n%v, got\n%v", rotation, r)
+	}
+
+	if _, err := NewSVD(NewMatrix([][]int{{1}})); !errors.Is(err, ErrKind) {
+		t.Errorf("Expected %v, got %v", ErrKind, err)
+	}
+}
@@ -0,0 +1,200 @@
+package matrix
+
+import (
+	"log"
+	"math"
+	"reflect"
+
+	"../vector"
+)
+
+// The transformations below are 4x4 Float32 matrices working on homogeneous coordinates
+// (x, y, z, w), see vector.Homogeneous. They combine with Mulm, the rightmost one is applied
+// first. Angles are in degrees, like everywhere else in the model.
+
+// Translation provides the matrix that moves points by offset
+func Translation(offset vector.Vector) Matrix {
+	check3D("matrix.Translation", offset)
+	return NewMatrix([][]float32{
+		{1.0, 0.0, 0.0, offset.Get(0).(float32)},
+		{0.0, 1.0, 0.0, offset.Get(1).(float32)},
+		{0.0, 0.0, 1.0, offset.Get(2).(float32)},
+		{0.0, 0.0, 0.0, 1.0},
+	})
+}
+
+// Scale provides the matrix that scales along x, y and z
+func Scale(scale vector.Vector) Matrix {
+	check3D("matrix.Scale", scale)
+	return NewMatrix([][]float32{
+		{scale.Get(0).(float32), 0.0, 0.0, 0.0},
+		{0.0, scale.Get(1).(float32), 0.0, 0.0},
+		{0.0, 0.0, scale.Get(2).(float32), 0.0},
+		{0.0, 0.0, 0.0, 1.0},
+	})
+}
+
+// RotationX provides the matrix that rotates counter-clockwise arround the x-axis, looking down on it
+func RotationX(angle float32) Matrix {
+	c, s := cosSin(angle)
+	return NewMatrix([][]float32{
+		{1.0, 0.0, 0.0, 0.0},
+		{0.0, c, -s, 0.0},
+		{0.0, s, c, 0.0},
+		{0.0, 0.0, 0.0, 1.0},
+	})
+}
+
+// RotationY provides the matrix that rotates counter-clockwise arround the y-axis, looking down on it
+func RotationY(angle float32) Matrix {
+	c, s := cosSin(angle)
+	return NewMatrix([][]float32{
+		{c, 0.0, s, 0.0},
+		{0.0, 1.0, 0.0, 0.0},
+		{-s, 0.0, c, 0.0},
+		{0.0, 0.0, 0.0, 1.0},
+	})
+}
+
+// RotationZ provides the matrix that rotates counter-clockwise arround the z-axis, looking down on it
+func RotationZ(angle float32) Matrix {
+	c, s := cosSin(angle)
+	return NewMatrix([][]float32{
+		{c, -s, 0.0, 0.0},
+		{s, c, 0.0, 0.0},
+		{0.0, 0.0, 1.0, 0.0},
+		{0.0, 0.0, 0.0, 1.0},
+	})
+}
+
+// RotationAxisAngle provides the matrix that rotates counter-clockwise arround axis, looking down on it
+// The axis doesn't have to be a unit vector, but it can't be zero.
+func RotationAxisAngle(axis vector.Vector, angle float32) Matrix {
+	check3D("matrix.RotationAxisAngle", axis)
+	length := axis.Abs()
+	if length == 0.0 {
+		log.Fatalf("matrix.RotationAxisAngle: axis can't be zero")
+	}
+	x := float64(axis.Get(0).(float32)) / length
+	y := float64(axis.Get(1).(float32)) / length
+	z := float64(axis.Get(2).(float32)) / length
+	radians := float64(angle) * math.Pi / 180.0
+	c, s := math.Cos(radians), math.Sin(radians)
+	t := 1.0 - c
+
+	return NewMatrix([][]float32{
+		{float32(t*x*x + c), float32(t*x*y - s*z), float32(t*x*z + s*y), 0.0},
+		{float32(t*x*y + s*z), float32(t*y*y + c), float32(t*y*z - s*x), 0.0},
+		{float32(t*x*z - s*y), float32(t*y*z + s*x), float32(t*z*z + c), 0.0},
+		{0.0, 0.0, 0.0, 1.0},
+	})
+}
+
+// LookAt provides the matrix that moves world coordinates into the coordinates of a viewer at eye
+// The viewer sits in the origin, looks down the negative z-axis towards target and has the y-axis
+// pointing up as far as up allows. When looking along up any perpendicular direction is used.
+func LookAt(eye vector.Vector, target vector.Vector, up vector.Vector) Matrix {
+	check3D("matrix.LookAt", eye)
+	check3D("matrix.LookAt", target)
+	check3D("matrix.LookAt", up)
+	if eye.Equal(target) {
+		log.Fatalf("matrix.LookAt: eye is the same as target")
+	}
+
+	// Build an orthonormal base: forward, side and up
+	forward := target.Sub(eye).Unit()
+	side := forward.Cross(up)
+	if side.Abs() < 1e-6 {
+		side = forward.Cross(vector.NewVector([]float32{0.0, 1.0, 0.0}))
+		if side.Abs() < 1e-6 {
+			side = forward.Cross(vector.NewVector([]float32{1.0, 0.0, 0.0}))
+		}
+	}
+	side = side.Unit()
+	up = side.Cross(forward)
+
+	return NewMatrix([][]float32{
+		{side.Get(0).(float32), side.Get(1).(float32), side.Get(2).(float32), float32(-side.Mulv(eye))},
+		{up.Get(0).(float32), up.Get(1).(float32), up.Get(2).(float32), float32(-up.Mulv(eye))},
+		{-forward.Get(0).(float32), -forward.Get(1).(float32), -forward.Get(2).(float32), float32(forward.Mulv(eye))},
+		{0.0, 0.0, 0.0, 1.0},
+	})
+}
+
+// Perspective provides the matrix that moves viewer coordinates into clip space
+// The view has a vertical field of view fov, in degrees, and is aspect times as wide as it is high.
+// After the perspective divide everything between near and far ends up in [-1..1] on all axis.
+func Perspective(fov float32, aspect float32, near float32, far float32) Matrix {
+	if fov <= 0.0 || fov >= 180.0 || aspect <= 0.0 || near <= 0.0 || far <= near {
+		log.Fatalf("matrix.Perspective: expects 0 < fov < 180, a positive aspect and 0 < near < far, got (fov:%f, a:%f, n:%f, f:%f)", fov, aspect, near, far)
+	}
+
+	f := float32(1.0 / math.Tan(float64(fov)*math.Pi/360.0))
+	return NewMatrix([][]float32{
+		{f / aspect, 0.0, 0.0, 0.0},
+		{0.0, f, 0.0, 0.0},
+		{0.0, 0.0, (far + near) / (near - far), 2.0 * far * near / (near - far)},
+		{0.0, 0.0, -1.0, 0.0},
+	})
+}
+
+// Orthographic provides the matrix that moves the box between left, right, bottom, top and the
+// near and far planes, in viewer coordinates, onto [-1..1] on all axis
+func Orthographic(left float32, right float32, bottom float32, top float32, near float32, far float32) Matrix {
+	if left == right || bottom == top || near == far {
+		log.Fatalf("matrix.Orthographic: expects a box with a size, got (l:%f, r:%f, b:%f, t:%f, n:%f, f:%f)", left, right, bottom, top, near, far)
+	}
+
+	return NewMatrix([][]float32{
+		{2.0 / (right - left), 0.0, 0.0, -(right + left) / (right - left)},
+		{0.0, 2.0 / (top - bottom), 0.0, -(top + bottom) / (top - bottom)},
+		{0.0, 0.0, -2.0 / (far - near), -(far + near) / (far - near)},
+		{0.0, 0.0, 0.0, 1.0},
+	})
+}
+
+// Affine combines a 3x3 Float32 transformation and a translation into one 4x4 matrix
+// The transformation is applied first.
+func Affine(linear Matrix, translation vector.Vector) Matrix {
+	if linear.Rows() != 3 || linear.Cols() != 3 || linear.Kind() != reflect.Float32 {
+		log.Fatalf("matrix.Affine: expects 3x3-Float32 matrix, got %dx%d-%v", linear.Rows(), linear.Cols(), linear.Kind())
+	}
+	check3D("matrix.Affine", translation)
+
+	m := UnitMatrix(4, 4, reflect.Float32)
+	for r := 0; r < 3; r++ {
+		for c := 0; c < 3; c++ {
+			m.Set(r, c, linear.Get(r, c))
+		}
+		m.Set(r, 3, translation.Get(r))
+	}
+	return m
+}
+
+// Linear provides the 3x3 transformation of a 4x4 matrix, without the translation
+func Linear(m Matrix) Matrix {
+	if m.Rows() != 4 || m.Cols() != 4 || m.Kind() != reflect.Float32 {
+		log.Fatalf("matrix.Linear: expects 4x4-Float32 matrix, got %dx%d-%v", m.Rows(), m.Cols(), m.Kind())
+	}
+
+	linear := ZeroMatrix(3, 3, reflect.Float32)
+	for r := 0; r < 3; r++ {
+		for c := 0; c < 3; c++ {
+			linear.Set(r, c, m.Get(r, c))
+		}
+	}
+	return linear
+}
+
+// cosSin provides the cosine and sine of an angle in degrees
+func cosSin(angle float32) (float32, float32) {
+	radians := float64(angle) * math.Pi / 180.0
+	return float32(math.Cos(radians)), float32(math.Sin(radians))
+}
+
+// check3D stops on anything but a 3D-Float32 vector
+func check3D(caller string, v vector.Vector) {
+	if v.Len() != 3 || v.Kind() != reflect.Float32 {
+		log.Fatalf("%s: expects 3D-Float32 vector, got %dD-%v", caller, v.Len(), v.Kind())
+	}
+}
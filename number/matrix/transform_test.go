@@ -0,0 +1,74 @@
+package matrix
+
+import (
+	"math"
+	"testing"
+
+	"../vector"
+)
+
+// near compares two Float32 vectors allowing for rounding errors
+func near(v vector.Vector, w vector.Vector) bool {
+	return v.Sub(w).Abs() < 1e-5
+}
+
+// apply moves a 3D point by a 4x4 transformation
+func apply(m Matrix, point []float32) vector.Vector {
+	return vector.PerspectiveDivide(m.Mulv(vector.Homogeneous(vector.NewVector(point))))
+}
+
+func Test_Transformations(t *testing.T) {
+	tests := []struct {
+		name     string
+		m        Matrix
+		point    []float32
+		expected []float32
+	}{
+		{"translation", Translation(vector.NewVector([]float32{1.0, 2.0, 3.0})), []float32{1.0, 1.0, 1.0}, []float32{2.0, 3.0, 4.0}},
+		{"scale", Scale(vector.NewVector([]float32{2.0, 3.0, -1.0})), []float32{1.0, 1.0, 1.0}, []float32{2.0, 3.0, -1.0}},
+		{"x", RotationX(90.0), []float32{0.0, 1.0, 0.0}, []float32{0.0, 0.0, 1.0}},
+		{"y", RotationY(90.0), []float32{0.0, 0.0, 1.0}, []float32{1.0, 0.0, 0.0}},
+		{"z", RotationZ(90.0), []float32{1.0, 0.0, 0.0}, []float32{0.0, 1.0, 0.0}},
+		{"axis", RotationAxisAngle(vector.NewVector([]float32{1.0, 1.0, 1.0}), 120.0), []float32{1.0, 0.0, 0.0}, []float32{0.0, 1.0, 0.0}},
+		{"combined", Translation(vector.NewVector([]float32{0.0, 0.0, 5.0})).Mulm(RotationZ(90.0)), []float32{1.0, 0.0, 0.0}, []float32{0.0, 1.0, 5.0}},
+	}
+	for _, test := range tests {
+		if r := apply(test.m, test.point); !near(r, vector.NewVector(test.expected)) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, r)
+		}
+	}
+
+	// The axis angle rotation matches the rotations arround the axis
+	if !RotationAxisAngle(vector.NewVector([]float32{0.0, 2.0, 0.0}), 30.0).Equal(RotationY(30.0)) {
+		t.Errorf("Expected %v, got %v", RotationY(30.0), RotationAxisAngle(vector.NewVector([]float32{0.0, 2.0, 0.0}), 30.0))
+	}
+
+	// Affine and Linear take the translation on and off
+	m := Translation(vector.NewVector([]float32{1.0, 2.0, 3.0})).Mulm(RotationX(30.0))
+	if !Affine(Linear(m), vector.NewVector([]float32{1.0, 2.0, 3.0})).Equal(m) {
+		t.Errorf("Expected %v, got %v", m, Affine(Linear(m), vector.NewVector([]float32{1.0, 2.0, 3.0})))
+	}
+}
+
+func Test_LookAtProjection(t *testing.T) {
+	eye := vector.NewVector([]float32{0.0, -10.0, 0.0})
+	view := LookAt(eye, vector.NewVector([]float32{0.0, 0.0, 0.0}), vector.NewVector([]float32{0.0, 0.0, 1.0}))
+
+	// The viewer looks down -z, with up along y and right along x
+	if r := apply(view, []float32{1.0, 0.0, 2.0}); !near(r, vector.NewVector([]float32{1.0, 2.0, -10.0})) {
+		t.Errorf("Expected (1, 2, -10), got %v", r)
+	}
+
+	// The near and far planes end up on -1 and 1
+	perspective := Perspective(90.0, 2.0, 1.0, 100.0)
+	if r := apply(perspective, []float32{2.0, 1.0, -1.0}); !near(r, vector.NewVector([]float32{1.0, 1.0, -1.0})) {
+		t.Errorf("Expected (1, 1, -1) on the near plane, got %v", r)
+	}
+	if r := apply(perspective, []float32{0.0, 0.0, -100.0}); math.Abs(float64(r.Get(2).(float32))-1.0) > 1e-5 {
+		t.Errorf("Expected depth 1 on the far plane, got %v", r)
+	}
+	orthographic := Orthographic(-4.0, 4.0, -1.0, 3.0, 1.0, 11.0)
+	if r := apply(orthographic, []float32{4.0, 1.0, -6.0}); !near(r, vector.NewVector([]float32{1.0, 0.0, 0.0})) {
+		t.Errorf("Expected (1, 0, 0), got %v", r)
+	}
+}
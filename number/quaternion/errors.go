@@ -0,0 +1,16 @@
+package quaternion
+
+import (
+	"errors"
+
+	"../../internal/failure"
+)
+
+// The reasons a quaternion can't be used as a rotation, check for them with errors.Is
+var (
+	ErrZero      = errors.New("quaternion can't be zero")
+	ErrNotFinite = errors.New("quaternion has to be finite")
+)
+
+// Error tells which quaternion operation failed and why, get it with errors.As
+type Error = failure.Error
@@ -0,0 +1,246 @@
+// Package quaternion provides rotations in 3D without gimbal lock, that interpolate smoothly
+// A rotation by angle arround the unit axis (ax, ay, az) is the unit quaternion
+// (ax*sin(angle/2), ay*sin(angle/2), az*sin(angle/2), cos(angle/2)), written (x, y, z, w) like glTF does.
+// Angles are in degrees and vectors and matrices are Float32, like in number/matrix.
+package quaternion
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"reflect"
+
+	"../matrix"
+	"../vector"
+)
+
+// Quaternion is x*i + y*j + z*k + w, used as a value
+type Quaternion struct {
+	x float32
+	y float32
+	z float32
+	w float32
+}
+
+// New creates the quaternion x*i + y*j + z*k + w
+func New(x float32, y float32, z float32, w float32) Quaternion {
+	return Quaternion{x, y, z, w}
+}
+
+// Identity provides the quaternion that doesn't rotate
+func Identity() Quaternion {
+	return Quaternion{0.0, 0.0, 0.0, 1.0}
+}
+
+// FromVector creates a quaternion from a 4D-Float32 vector (x, y, z, w)
+func FromVector(v vector.Vector) Quaternion {
+	if v.Len() != 4 || v.Kind() != reflect.Float32 {
+		log.Fatalf("quaternion.FromVector: expects 4D-Float32 vector, got %dD-%v", v.Len(), v.Kind())
+	}
+	return Quaternion{v.Get(0).(float32), v.Get(1).(float32), v.Get(2).(float32), v.Get(3).(float32)}
+}
+
+// FromAxisAngle provides the rotation counter-clockwise arround axis, looking down on it
+// The axis doesn't have to be a unit vector, but it can't be zero.
+func FromAxisAngle(axis vector.Vector, angle float32) Quaternion {
+	if axis.Len() != 3 || axis.Kind() != reflect.Float32 {
+		log.Fatalf("quaternion.FromAxisAngle: expects 3D-Float32 axis, got %dD-%v", axis.Len(), axis.Kind())
+	}
+	length := axis.Abs()
+	if length == 0.0 {
+		log.Fatalf("quaternion.FromAxisAngle: axis can't be zero")
+	}
+	radians := float64(angle) * math.Pi / 180.0
+	s := math.Sin(radians/2.0) / length
+	return Quaternion{
+		float32(float64(axis.Get(0).(float32)) * s),
+		float32(float64(axis.Get(1).(float32)) * s),
+		float32(float64(axis.Get(2).(float32)) * s),
+		float32(math.Cos(radians / 2.0)),
+	}
+}
+
+// FromEuler provides the rotation arround x, then y and then z, in degrees
+// It is the same rotation as Z * Y * X in number/matrix, which Part.SetRotation uses.
+func FromEuler(rotation vector.Vector) Quaternion {
+	if rotation.Len() != 3 || rotation.Kind() != reflect.Float32 {
+		log.Fatalf("quaternion.FromEuler: expects 3D-Float32 vector, got %dD-%v", rotation.Len(), rotation.Kind())
+	}
+	x := FromAxisAngle(vector.NewVector([]float32{1.0, 0.0, 0.0}), rotation.Get(0).(float32))
+	y := FromAxisAngle(vector.NewVector([]float32{0.0, 1.0, 0.0}), rotation.Get(1).(float32))
+	z := FromAxisAngle(vector.NewVector([]float32{0.0, 0.0, 1.0}), rotation.Get(2).(float32))
+	return z.Mul(y.Mul(x))
+}
+
+// FromMatrix provides the rotation of a 3x3 or 4x4 Float32 rotation matrix
+// Any translation in a 4x4 matrix is ignored, the rest has to be a proper rotation.
+func FromMatrix(rotation matrix.Matrix) Quaternion {
+	size := rotation.Rows()
+	if (size != 3 && size != 4) || rotation.Cols() != size || rotation.Kind() != reflect.Float32 {
+		log.Fatalf("quaternion.FromMatrix: expects 3x3 or 4x4-Float32 matrix, got %dx%d-%v", rotation.Rows(), rotation.Cols(), rotation.Kind())
+	}
+	var m [3][3]float64
+	for r := 0; r < 3; r++ {
+		for c := 0; c < 3; c++ {
+			m[r][c] = float64(rotation.Get(r, c).(float32))
+		}
+	}
+
+	// Pick the largest of the four to divide by (Shepperd)
+	var x, y, z, w float64
+	switch trace := m[0][0] + m[1][1] + m[2][2]; {
+	case trace > 0.0:
+		s := math.Sqrt(trace+1.0) * 2.0
+		w, x, y, z = s/4.0, (m[2][1]-m[1][2])/s, (m[0][2]-m[2][0])/s, (m[1][0]-m[0][1])/s
+	case m[0][0] > m[1][1] && m[0][0] > m[2][2]:
+		s := math.Sqrt(1.0+m[0][0]-m[1][1]-m[2][2]) * 2.0
+		w, x, y, z = (m[2][1]-m[1][2])/s, s/4.0, (m[0][1]+m[1][0])/s, (m[0][2]+m[2][0])/s
+	case m[1][1] > m[2][2]:
+		s := math.Sqrt(1.0+m[1][1]-m[0][0]-m[2][2]) * 2.0
+		w, x, y, z = (m[0][2]-m[2][0])/s, (m[0][1]+m[1][0])/s, s/4.0, (m[1][2]+m[2][1])/s
+	default:
+		s := math.Sqrt(1.0+m[2][2]-m[0][0]-m[1][1]) * 2.0
+		w, x, y, z = (m[1][0]-m[0][1])/s, (m[0][2]+m[2][0])/s, (m[1][2]+m[2][1])/s, s/4.0
+	}
+	return Quaternion{float32(x), float32(y), float32(z), float32(w)}
+}
+
+// Mul provides the product of two quaternions, the rotation r followed by q
+func (q Quaternion) Mul(r Quaternion) Quaternion {
+	return Quaternion{
+		q.w*r.x + q.x*r.w + q.y*r.z - q.z*r.y,
+		q.w*r.y - q.x*r.z + q.y*r.w + q.z*r.x,
+		q.w*r.z + q.x*r.y - q.y*r.x + q.z*r.w,
+		q.w*r.w - q.x*r.x - q.y*r.y - q.z*r.z,
+	}
+}
+
+// Abs provides the length of the quaternion, 1 for rotations
+func (q Quaternion) Abs() float64 {
+	return math.Sqrt(q.dot(q))
+}
+
+// Normalize provides the quaternion with length 1 in the same direction, to undo drift from rounding errors
+func (q Quaternion) Normalize() Quaternion {
+	r, err := q.TryNormalize()
+	if err != nil {
+		log.Fatal(err)
+	}
+	return r
+}
+
+// TryNormalize is Normalize, failing with ErrZero or ErrNotFinite for quaternions without a direction
+func (q Quaternion) TryNormalize() (Quaternion, error) {
+	if err := q.check("Quaternion.Normalize"); err != nil {
+		return Quaternion{}, err
+	}
+	return q.scale(1.0 / q.Abs()), nil
+}
+
+// Conjugate provides the quaternion with x, y and z negated, the opposite rotation of a unit quaternion
+func (q Quaternion) Conjugate() Quaternion {
+	return Quaternion{-q.x, -q.y, -q.z, q.w}
+}
+
+// Inverse provides the quaternion that multiplies with q to the identity
+func (q Quaternion) Inverse() Quaternion {
+	r, err := q.TryInverse()
+	if err != nil {
+		log.Fatal(err)
+	}
+	return r
+}
+
+// TryInverse is Inverse, failing with ErrZero or ErrNotFinite for quaternions without an inverse
+func (q Quaternion) TryInverse() (Quaternion, error) {
+	if err := q.check("Quaternion.Inverse"); err != nil {
+		return Quaternion{}, err
+	}
+	return q.Conjugate().scale(1.0 / q.dot(q)), nil
+}
+
+// Slerp interpolates between two unit quaternions along the shortest arc
+// At t 0 it is q, at t 1 it is r, in between it rotates at a constant speed.
+func (q Quaternion) Slerp(r Quaternion, t float32) Quaternion {
+	cos := q.dot(r)
+	if cos < 0.0 {
+		r = r.scale(-1.0)
+		cos = -cos
+	}
+
+	// Close together a straight line is good enough, and avoids dividing by zero
+	if cos > 0.9995 {
+		return q.add(r.add(q.scale(-1.0)).scale(float64(t))).Normalize()
+	}
+	angle := math.Acos(cos)
+	wq := math.Sin((1.0-float64(t))*angle) / math.Sin(angle)
+	wr := math.Sin(float64(t)*angle) / math.Sin(angle)
+	return q.scale(wq).add(r.scale(wr))
+}
+
+// Rotate turns a 3D-Float32 vector by the rotation of the quaternion
+func (q Quaternion) Rotate(v vector.Vector) vector.Vector {
+	return q.Matrix().Mulv(v)
+}
+
+// Matrix provides the 3x3 Float32 rotation matrix, use matrix.Affine to combine it with a translation
+// The quaternion doesn't have to be a unit quaternion, only its direction counts.
+func (q Quaternion) Matrix() matrix.Matrix {
+	m, err := q.TryMatrix()
+	if err != nil {
+		log.Fatal(err)
+	}
+	return m
+}
+
+// TryMatrix is Matrix, failing with ErrZero or ErrNotFinite for quaternions without a direction
+func (q Quaternion) TryMatrix() (matrix.Matrix, error) {
+	if err := q.check("Quaternion.Matrix"); err != nil {
+		return nil, err
+	}
+	q = q.scale(1.0 / q.Abs())
+	x, y, z, w := q.x, q.y, q.z, q.w
+	return matrix.NewMatrix([][]float32{
+		{1 - 2*(y*y+z*z), 2 * (x*y - z*w), 2 * (x*z + y*w)},
+		{2 * (x*y + z*w), 1 - 2*(x*x+z*z), 2 * (y*z - x*w)},
+		{2 * (x*z - y*w), 2 * (y*z + x*w), 1 - 2*(x*x+y*y)},
+	}), nil
+}
+
+// Vector provides the quaternion as a 4D-Float32 vector (x, y, z, w)
+func (q Quaternion) Vector() vector.Vector {
+	return vector.NewVector([]float32{q.x, q.y, q.z, q.w})
+}
+
+// String implements the Stringer interface
+func (q Quaternion) String() string {
+	return q.Vector().String()
+}
+
+// dot provides the inner product of two quaternions
+func (q Quaternion) dot(r Quaternion) float64 {
+	return float64(q.x)*float64(r.x) + float64(q.y)*float64(r.y) + float64(q.z)*float64(r.z) + float64(q.w)*float64(r.w)
+}
+
+// add provides the sum of two quaternions
+func (q Quaternion) add(r Quaternion) Quaternion {
+	return Quaternion{q.x + r.x, q.y + r.y, q.z + r.z, q.w + r.w}
+}
+
+// scale provides the quaternion multiplied by f
+func (q Quaternion) scale(f float64) Quaternion {
+	return Quaternion{float32(float64(q.x) * f), float32(float64(q.y) * f), float32(float64(q.z) * f), float32(float64(q.w) * f)}
+}
+
+// check fails for quaternions that don't give a direction, zero or with infinite or NaN values
+func (q Quaternion) check(op string) error {
+	for _, f := range []float32{q.x, q.y, q.z, q.w} {
+		if math.IsInf(float64(f), 0) || math.IsNaN(float64(f)) {
+			return &Error{Op: op, Err: ErrNotFinite, Detail: fmt.Sprintf("got %v", q)}
+		}
+	}
+	if q.dot(q) == 0.0 {
+		return &Error{Op: op, Err: ErrZero}
+	}
+	return nil
+}
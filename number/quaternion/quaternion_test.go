@@ -0,0 +1,149 @@
+package quaternion
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"../matrix"
+	"../vector"
+)
+
+// near compares two Float32 vectors allowing for rounding errors
+func near(v vector.Vector, w vector.Vector) bool {
+	return v.Sub(w).Abs() < 1e-5
+}
+
+// nearMatrix compares two Float32 matrices allowing for rounding errors
+func nearMatrix(m matrix.Matrix, n matrix.Matrix) bool {
+	for r := 0; r < m.Rows(); r++ {
+		for c := 0; c < m.Cols(); c++ {
+			if math.Abs(float64(m.Get(r, c).(float32)-n.Get(r, c).(float32))) > 1e-5 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func Test_FromAxisAngle(t *testing.T) {
+	q := FromAxisAngle(vector.NewVector([]float32{0.0, 0.0, 2.0}), 90.0)
+	if !near(q.Vector(), vector.NewVector([]float32{0.0, 0.0, float32(math.Sqrt(0.5)), float32(math.Sqrt(0.5))})) {
+		t.Errorf("Expected 90 degrees arround z, got %v", q)
+	}
+
+	tests := []struct {
+		axis  []float32
+		angle float32
+		point []float32
+	}{
+		{[]float32{1.0, 0.0, 0.0}, 90.0, []float32{0.0, 1.0, 0.0}},
+		{[]float32{0.0, 1.0, 0.0}, -30.0, []float32{1.0, 2.0, 3.0}},
+		{[]float32{1.0, 1.0, 1.0}, 120.0, []float32{1.0, 0.0, 0.0}},
+		{[]float32{0.3, -0.5, 2.0}, 250.0, []float32{-1.0, 0.5, 4.0}},
+	}
+	for i, test := range tests {
+		axis, point := vector.NewVector(test.axis), vector.NewVector(test.point)
+		q := FromAxisAngle(axis, test.angle)
+		expected := matrix.Linear(matrix.RotationAxisAngle(axis, test.angle))
+		if !nearMatrix(q.Matrix(), expected) || !near(q.Rotate(point), expected.Mulv(point)) {
+			t.Errorf("Test %d: expected\n%v, got\n%v", i, expected, q.Matrix())
+		}
+		if r := FromMatrix(q.Matrix()); !near(r.Vector(), q.Vector()) && !near(r.Vector(), q.Vector().Muls(float32(-1.0))) {
+			t.Errorf("Test %d: expected %v back from the matrix, got %v", i, q, r)
+		}
+	}
+}
+
+func Test_FromEuler(t *testing.T) {
+	rotation := vector.NewVector([]float32{30.0, -45.0, 120.0})
+	expected := matrix.Linear(matrix.RotationZ(120.0).Mulm(matrix.RotationY(-45.0).Mulm(matrix.RotationX(30.0))))
+	if q := FromEuler(rotation); !nearMatrix(q.Matrix(), expected) {
+		t.Errorf("Expected\n%v, got\n%v", expected, q.Matrix())
+	}
+
+	// The translation of a 4x4 matrix is ignored
+	transform := matrix.Translation(vector.NewVector([]float32{1.0, 2.0, 3.0})).Mulm(matrix.RotationZ(120.0))
+	if q := FromMatrix(transform); !nearMatrix(q.Matrix(), matrix.Linear(matrix.RotationZ(120.0))) {
+		t.Errorf("Expected 120 degrees arround z, got %v", q)
+	}
+}
+
+func Test_Operations(t *testing.T) {
+	a := FromAxisAngle(vector.NewVector([]float32{1.0, 2.0, 3.0}), 40.0)
+	b := FromAxisAngle(vector.NewVector([]float32{-1.0, 0.0, 1.0}), 75.0)
+
+	// Products go like the matrices, the right one rotates first
+	if r := a.Mul(b); !nearMatrix(r.Matrix(), a.Matrix().Mulm(b.Matrix())) {
+		t.Errorf("Expected\n%v, got\n%v", a.Matrix().Mulm(b.Matrix()), r.Matrix())
+	}
+	if r := a.Mul(a.Conjugate()); !near(r.Vector(), Identity().Vector()) {
+		t.Errorf("Expected the identity, got %v", r)
+	}
+	c := New(1.0, 2.0, -2.0, 4.0)
+	if r := c.Mul(c.Inverse()); !near(r.Vector(), Identity().Vector()) {
+		t.Errorf("Expected the identity, got %v", r)
+	}
+	if r := c.Normalize(); math.Abs(r.Abs()-1.0) > 1e-6 || !near(r.Vector(), c.Vector().Divs(float32(5.0))) {
+		t.Errorf("Expected a unit quaternion, got %v", r)
+	}
+	if r := c.Matrix(); !nearMatrix(r, c.Normalize().Matrix()) {
+		t.Errorf("Expected only the direction to count, got\n%v", r)
+	}
+}
+
+func Test_TryNormalize(t *testing.T) {
+	infinite := float32(math.Inf(1))
+	tests := []struct {
+		q        Quaternion
+		expected error
+	}{
+		{New(0.0, 0.0, 0.0, 0.0), ErrZero},
+		{New(0.0, infinite, 0.0, 1.0), ErrNotFinite},
+		{New(float32(math.NaN()), 0.0, 0.0, 1.0), ErrNotFinite},
+	}
+	for i, test := range tests {
+		_, normalize := test.q.TryNormalize()
+		_, inverse := test.q.TryInverse()
+		_, rotation := test.q.TryMatrix()
+		var e *Error
+		if !errors.Is(normalize, test.expected) || !errors.Is(inverse, test.expected) || !errors.Is(rotation, test.expected) ||
+			!errors.As(normalize, &e) || e.Op != "Quaternion.Normalize" {
+			t.Errorf("Test %d: expected %v, got %v, %v and %v", i, test.expected, normalize, inverse, rotation)
+		}
+	}
+
+	if r, err := New(0.0, 0.0, 0.0, 2.0).TryNormalize(); err != nil || r != Identity() {
+		t.Errorf("Expected the identity, got %v and %v", r, err)
+	}
+}
+
+func Test_Slerp(t *testing.T) {
+	z := vector.NewVector([]float32{0.0, 0.0, 1.0})
+	from, to := FromAxisAngle(z, 10.0), FromAxisAngle(z, 100.0)
+	tests := []struct {
+		t     float32
+		angle float32
+	}{
+		{0.0, 10.0},
+		{0.25, 32.5},
+		{0.5, 55.0},
+		{1.0, 100.0},
+	}
+	for _, test := range tests {
+		if r := from.Slerp(to, test.t); !near(r.Vector(), FromAxisAngle(z, test.angle).Vector()) {
+			t.Errorf("At %f: expected %v, got %v", test.t, FromAxisAngle(z, test.angle), r)
+		}
+	}
+
+	// The same rotation with the opposite sign takes the shortest arc
+	negated := New(-to.x, -to.y, -to.z, -to.w)
+	if r := from.Slerp(negated, 0.5); !nearMatrix(r.Matrix(), FromAxisAngle(z, 55.0).Matrix()) {
+		t.Errorf("Expected 55 degrees arround z, got %v", r)
+	}
+
+	// Close together it interpolates linearly
+	if r := from.Slerp(FromAxisAngle(z, 10.5), 0.5); !near(r.Vector(), FromAxisAngle(z, 10.25).Vector()) {
+		t.Errorf("Expected 10.25 degrees arround z, got %v", r)
+	}
+}
@@ -0,0 +1,167 @@
+package typed
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"strings"
+
+	"../matrix"
+)
+
+// Matrix is a mathematical matrix of numbers of type T, stored row by row
+type Matrix[T Number] struct {
+	rows  int
+	cols  int
+	cells []T
+}
+
+// ZeroMatrix creates a matrix 'rows' high and 'cols' wide
+func ZeroMatrix[T Number](rows int, cols int) Matrix[T] {
+	if rows <= 0 || cols <= 0 {
+		log.Fatalf("typed.ZeroMatrix: expects a positive size, got (%d, %d)", rows, cols)
+	}
+	return Matrix[T]{rows, cols, make([]T, rows*cols)}
+}
+
+// UnitMatrix creates a matrix with all ones across the main diagonal
+func UnitMatrix[T Number](rows int, cols int) Matrix[T] {
+	m := ZeroMatrix[T](rows, cols)
+	for i := 0; i < rows && i < cols; i++ {
+		m.cells[i*cols+i] = 1
+	}
+	return m
+}
+
+// NewMatrix creates a matrix based on a list of rows
+func NewMatrix[T Number](values [][]T) Matrix[T] {
+	if len(values) == 0 || len(values[0]) == 0 {
+		log.Fatal(&matrix.Error{Op: "typed.NewMatrix", Err: matrix.ErrEmpty})
+	}
+	m := ZeroMatrix[T](len(values), len(values[0]))
+	for r, row := range values {
+		if len(row) != m.cols {
+			log.Fatal(&matrix.Error{Op: "typed.NewMatrix", Err: matrix.ErrIrregular, Detail: fmt.Sprintf("row %d expected %d cols, got %d", r, m.cols, len(row))})
+		}
+		copy(m.cells[r*m.cols:], row)
+	}
+	return m
+}
+
+// FromMatrix copies a matrix.Matrix holding numbers of type T
+func FromMatrix[T Number](n matrix.Matrix) (Matrix[T], error) {
+	m := Matrix[T]{n.Rows(), n.Cols(), make([]T, n.Rows()*n.Cols())}
+	for r := 0; r < m.rows; r++ {
+		for c := 0; c < m.cols; c++ {
+			value, ok := n.Get(r, c).(T)
+			if !ok {
+				return Matrix[T]{}, &matrix.Error{Op: "typed.FromMatrix", Err: matrix.ErrKind, Detail: fmt.Sprintf("got %v", n.Kind())}
+			}
+			m.cells[r*m.cols+c] = value
+		}
+	}
+	return m, nil
+}
+
+// Matrix copies the matrix into a matrix.Matrix
+func (m Matrix[T]) Matrix() matrix.Matrix {
+	values := make([][]T, m.rows)
+	for r := range values {
+		values[r] = m.cells[r*m.cols : (r+1)*m.cols]
+	}
+	return matrix.NewMatrix(values)
+}
+
+// Mulv provides the matrix multiplied by a column vector
+func (m Matrix[T]) Mulv(v Vector[T]) Vector[T] {
+	if m.cols != len(v.cells) {
+		log.Fatalf("typed.Matrix.Mulv: expected vector length %d, got %d", m.cols, len(v.cells))
+	}
+
+	r := Vector[T]{make([]T, m.rows)}
+	for row := 0; row < m.rows; row++ {
+		var sum T
+		for c, value := range m.cells[row*m.cols : (row+1)*m.cols] {
+			sum += value * v.cells[c]
+		}
+		r.cells[row] = sum
+	}
+	return r
+}
+
+// Mulm provides the product of two matrices
+func (m Matrix[T]) Mulm(n Matrix[T]) Matrix[T] {
+	if m.cols != n.rows {
+		log.Fatalf("typed.Matrix.Mulm: expected matrix with %d rows, got %d", m.cols, n.rows)
+	}
+
+	result := Matrix[T]{m.rows, n.cols, make([]T, m.rows*n.cols)}
+	for r := 0; r < m.rows; r++ {
+		for k := 0; k < m.cols; k++ {
+			value := m.cells[r*m.cols+k]
+			for c := 0; c < n.cols; c++ {
+				result.cells[r*n.cols+c] += value * n.cells[k*n.cols+c]
+			}
+		}
+	}
+	return result
+}
+
+// Kind tells which kind of numbers the matrix holds, like the Kind of a matrix.Matrix
+func (m Matrix[T]) Kind() reflect.Kind {
+	return reflect.TypeOf(m.cells).Elem().Kind()
+}
+
+// Rows provides the height of the matrix
+func (m Matrix[T]) Rows() int {
+	return m.rows
+}
+
+// Cols provides the width of the matrix
+func (m Matrix[T]) Cols() int {
+	return m.cols
+}
+
+// Get provides a value from the matrix
+func (m Matrix[T]) Get(row int, col int) T {
+	m.checkIndex("Get", row, col)
+	return m.cells[row*m.cols+col]
+}
+
+// Set changes a value in the matrix, matrices share their values like a slice does
+func (m Matrix[T]) Set(row int, col int, value T) {
+	m.checkIndex("Set", row, col)
+	m.cells[row*m.cols+col] = value
+}
+
+// Equal checks if two matrices are the same
+func (m Matrix[T]) Equal(n Matrix[T]) bool {
+	if n.rows != m.rows || n.cols != m.cols {
+		log.Fatalf("typed.Matrix.Equal: dimensions (%d, %d) and (%d, %d) do not match", m.rows, m.cols, n.rows, n.cols)
+	}
+	for i, value := range m.cells {
+		if value != n.cells[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// String implements the Stringer interface, like the String of a matrix.Matrix
+func (m Matrix[T]) String() string {
+	var sb strings.Builder
+	for r := 0; r < m.rows; r++ {
+		for c := 0; c < m.cols; c++ {
+			sb.WriteString(fmt.Sprintf("%v ", m.cells[r*m.cols+c]))
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// checkIndex stops on positions outside the matrix
+func (m Matrix[T]) checkIndex(caller string, row int, col int) {
+	if row < 0 || row >= m.rows || col < 0 || col >= m.cols {
+		log.Fatalf("typed.Matrix.%s: index (%d, %d) out of bounds, expected (<%d, <%d)", caller, row, col, m.rows, m.cols)
+	}
+}
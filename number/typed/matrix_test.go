@@ -0,0 +1,61 @@
+package typed
+
+import (
+	"errors"
+	"testing"
+
+	"../matrix"
+)
+
+func Test_Matrix(t *testing.T) {
+	m := NewMatrix([][]int{
+		{1, 2, 3},
+		{4, 5, 6},
+	})
+	if r := m.Mulv(NewVector([]int{1, 0, -1})); !r.Equal(NewVector([]int{-2, -2})) {
+		t.Errorf("Expected [-2, -2], got %v", r)
+	}
+	n := NewMatrix([][]int{
+		{1, 0},
+		{0, 1},
+		{1, 1},
+	})
+	expected := NewMatrix([][]int{
+		{4, 5},
+		{10, 11},
+	})
+	if r := m.Mulm(n); !r.Equal(expected) {
+		t.Errorf("Expected %v, got %v", expected, r)
+	}
+	if r := m.Mulm(UnitMatrix[int](3, 3)); !r.Equal(m) {
+		t.Errorf("Expected %v, got %v", m, r)
+	}
+
+	m.Set(1, 2, 7)
+	if m.Get(1, 2) != 7 || m.Rows() != 2 || m.Cols() != 3 {
+		t.Errorf("Expected 7 at (1, 2), got %v", m)
+	}
+}
+
+func Test_MatrixConversion(t *testing.T) {
+	m := matrix.NewMatrix([][]float32{
+		{1.0, 2.0},
+		{3.0, 4.0},
+	})
+	typed, err := FromMatrix[float32](m)
+	if err != nil || typed.Get(1, 0) != 3.0 {
+		t.Fatalf("Expected %v, got %v %v", m, typed, err)
+	}
+	if back := typed.Mulm(typed).Matrix(); !back.Equal(m.Mulm(m)) {
+		t.Errorf("Expected %v, got %v", m.Mulm(m), back)
+	}
+	if typed.String() != m.String() {
+		t.Errorf("Expected %q, got %q", m.String(), typed.String())
+	}
+
+	// The kind has to match
+	_, err = FromMatrix[int](m)
+	if !errors.Is(err, matrix.ErrKind) {
+		t.Errorf("Expected %v, got %v", matrix.ErrKind, err)
+	}
+}
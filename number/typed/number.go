@@ -0,0 +1,17 @@
+// Package typed implements vectors and matrices with type parameters
+// They offer the operations of the vector and matrix packages without reflection: the type
+// of the cells is known at compile time. Use them where speed matters, and convert from and
+// to vector.Vector and matrix.Matrix to work with the rest of the code.
+package typed
+
+// Number is any of the kinds a vector or matrix can hold
+type Number interface {
+	int | int8 | int16 | int32 | int64 |
+		uint | uint8 | uint16 | uint32 | uint64 |
+		float32 | float64
+}
+
+// Float is any of the kinds that can hold fractions
+type Float interface {
+	float32 | float64
+}
@@ -0,0 +1,177 @@
+package typed
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"reflect"
+	"strings"
+
+	"../vector"
+)
+
+// Vector is a mathematical vector of numbers of type T
+type Vector[T Number] struct {
+	cells []T
+}
+
+// ZeroVector creates a vector of the requested size set to the origin
+func ZeroVector[T Number](dimension int) Vector[T] {
+	if dimension <= 0 {
+		log.Fatalf("typed.ZeroVector: expects a positive dimension, got %d", dimension)
+	}
+	return Vector[T]{make([]T, dimension)}
+}
+
+// NewVector creates a vector based on a list of values
+func NewVector[T Number](values []T) Vector[T] {
+	if len(values) == 0 {
+		log.Fatal(&vector.Error{Op: "typed.NewVector", Err: vector.ErrEmpty})
+	}
+	return Vector[T]{append([]T(nil), values...)}
+}
+
+// FromVector copies a vector.Vector holding numbers of type T
+func FromVector[T Number](v vector.Vector) (Vector[T], error) {
+	r := Vector[T]{make([]T, v.Len())}
+	for i := range r.cells {
+		value, ok := v.Get(i).(T)
+		if !ok {
+			return Vector[T]{}, &vector.Error{Op: "typed.FromVector", Err: vector.ErrKind, Detail: fmt.Sprintf("got %v", v.Kind())}
+		}
+		r.cells[i] = value
+	}
+	return r, nil
+}
+
+// Vector copies the vector into a vector.Vector
+func (v Vector[T]) Vector() vector.Vector {
+	return vector.NewVector(v.cells)
+}
+
+// Unit provides a vector with length 1 in the direction of the vector
+func Unit[T Float](v Vector[T]) Vector[T] {
+	return v.Divs(T(v.Abs()))
+}
+
+// Abs provides the euclidian length of the vector
+func (v Vector[T]) Abs() float64 {
+	l := 0.0
+	for _, c := range v.cells {
+		l += float64(c) * float64(c)
+	}
+	return math.Sqrt(l)
+}
+
+// Add provides the sum of two vectors
+func (v Vector[T]) Add(w Vector[T]) Vector[T] {
+	v.check("Add", w)
+	r := Vector[T]{make([]T, len(v.cells))}
+	for i, c := range v.cells {
+		r.cells[i] = c + w.cells[i]
+	}
+	return r
+}
+
+// Sub provides the difference of two vectors
+func (v Vector[T]) Sub(w Vector[T]) Vector[T] {
+	v.check("Sub", w)
+	r := Vector[T]{make([]T, len(v.cells))}
+	for i, c := range v.cells {
+		r.cells[i] = c - w.cells[i]
+	}
+	return r
+}
+
+// Muls provides the vector multiplied by a scalar
+func (v Vector[T]) Muls(s T) Vector[T] {
+	r := Vector[T]{make([]T, len(v.cells))}
+	for i, c := range v.cells {
+		r.cells[i] = c * s
+	}
+	return r
+}
+
+// Divs provides the vector divided by a scalar
+func (v Vector[T]) Divs(s T) Vector[T] {
+	r := Vector[T]{make([]T, len(v.cells))}
+	for i, c := range v.cells {
+		r.cells[i] = c / s
+	}
+	return r
+}
+
+// Mulv provides the inner product of two vectors
+// It is calculated in float64, like Abs, so small integer kinds don't overflow.
+func (v Vector[T]) Mulv(w Vector[T]) float64 {
+	v.check("Mulv", w)
+	r := 0.0
+	for i, c := range v.cells {
+		r += float64(c) * float64(w.cells[i])
+	}
+	return r
+}
+
+// Kind tells which kind of numbers the vector holds, like the Kind of a vector.Vector
+func (v Vector[T]) Kind() reflect.Kind {
+	return reflect.TypeOf(v.cells).Elem().Kind()
+}
+
+// Len provides the dimension of the vector
+func (v Vector[T]) Len() int {
+	return len(v.cells)
+}
+
+// Get provides a value from the vector
+func (v Vector[T]) Get(i int) T {
+	v.checkIndex("Get", i)
+	return v.cells[i]
+}
+
+// Set changes a value in the vector, vectors share their values like a slice does
+func (v Vector[T]) Set(i int, value T) Vector[T] {
+	v.checkIndex("Set", i)
+	v.cells[i] = value
+	return v
+}
+
+// Equal checks if two vectors are the same
+func (v Vector[T]) Equal(w Vector[T]) bool {
+	v.check("Equal", w)
+	for i, c := range v.cells {
+		if c != w.cells[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// String implements the Stringer interface
+func (v Vector[T]) String() string {
+	var s strings.Builder
+
+	s.WriteString("[")
+	for i, c := range v.cells {
+		if i > 0 {
+			s.WriteString(", ")
+		}
+		s.WriteString(fmt.Sprintf("%v", c))
+	}
+	s.WriteString("]")
+
+	return s.String()
+}
+
+// check stops on vectors of different dimensions
+func (v Vector[T]) check(caller string, w Vector[T]) {
+	if len(w.cells) != len(v.cells) {
+		log.Fatalf("typed.Vector.%s: dimensions %d and %d do not match", caller, len(v.cells), len(w.cells))
+	}
+}
+
+// checkIndex stops on positions outside the vector
+func (v Vector[T]) checkIndex(caller string, i int) {
+	if i < 0 || i >= len(v.cells) {
+		log.Fatalf("typed.Vector.%s: index %d out of bounds, expected < %d", caller, i, len(v.cells))
+	}
+}
@@ -0,0 +1,63 @@
+package typed
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	"../vector"
+)
+
+func Test_Vector(t *testing.T) {
+	v := NewVector([]int{1, 2, 3})
+	w := NewVector([]int{4, 5, 6})
+	if sum := v.Add(w); !sum.Equal(NewVector([]int{5, 7, 9})) {
+		t.Errorf("Expected [5, 7, 9], got %v", sum)
+	}
+	if difference := w.Sub(v); !difference.Equal(NewVector([]int{3, 3, 3})) {
+		t.Errorf("Expected [3, 3, 3], got %v", difference)
+	}
+	if product := v.Mulv(w); product != 32.0 {
+		t.Errorf("Expected 32, got %v", product)
+	}
+	if product := NewVector([]int8{100, 100}).Mulv(NewVector([]int8{100, 100})); product != 20000.0 {
+		t.Errorf("Expected 20000, got %v", product)
+	}
+	if scaled := w.Muls(2).Divs(3); !scaled.Equal(NewVector([]int{2, 3, 4})) {
+		t.Errorf("Expected [2, 3, 4], got %v", scaled)
+	}
+
+	// Units only exist for floats
+	u := Unit(NewVector([]float32{3.0, 0.0, 4.0}))
+	if math.Abs(u.Abs()-1.0) > 1e-6 || u.Get(2) != 0.8 {
+		t.Errorf("Expected [0.6, 0, 0.8], got %v", u)
+	}
+
+	// NewVector copies the values, Set changes them in place
+	values := []float64{1.0, 2.0}
+	f := NewVector(values)
+	values[0] = 9.0
+	f.Set(1, 3.0)
+	if f.String() != "[1, 3]" || f.Kind() != reflect.Float64 {
+		t.Errorf("Expected [1, 3], got %v", f)
+	}
+}
+
+func Test_VectorConversion(t *testing.T) {
+	v := vector.NewVector([]float32{1.0, 2.0, 3.0})
+	typed, err := FromVector[float32](v)
+	if err != nil || typed.Len() != 3 || typed.Get(2) != 3.0 {
+		t.Fatalf("Expected [1, 2, 3], got %v %v", typed, err)
+	}
+	if back := typed.Muls(2.0).Vector(); !back.Equal(v.Muls(float32(2.0))) {
+		t.Errorf("Expected %v, got %v", v.Muls(float32(2.0)), back)
+	}
+
+	// The kind has to match
+	_, err = FromVector[float64](v)
+	var e *vector.Error
+	if !errors.Is(err, vector.ErrKind) || !errors.As(err, &e) || e.Op != "typed.FromVector" {
+		t.Errorf("Expected %v, got %v", vector.ErrKind, err)
+	}
+}
@@ -0,0 +1,17 @@
+package vector
+
+import (
+	"errors"
+
+	"../../internal/failure"
+)
+
+// The reasons a vector can't be made or used, check for them with errors.Is
+var (
+	ErrNotArray = errors.New("expected an array or slice")
+	ErrEmpty    = errors.New("cannot create from an empty array")
+	ErrKind     = errors.New("unsupported kind")
+)
+
+// Error tells which vector operation failed and why, get it with errors.As
+type Error = failure.Error
@@ -0,0 +1,429 @@
+package vector
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"math/rand"
+	"reflect"
+	"strings"
+)
+
+// Vec3 is a 3D Float32 vector without reflection, the factories return it for 3 float32 values
+// Vectors share their values, so it is used as a pointer like the cells of the generic vector.
+type Vec3 [3]float32
+
+// Vec4 is a 4D Float32 vector without reflection, used for homogeneous coordinates
+type Vec4 [4]float32
+
+// Unit provides a vector with length 1 in the direction of the vector
+func (v *Vec3) Unit() Vector {
+	return v.Divs(float32(v.Abs()))
+}
+
+// Abs provides the euclidian length of the vector
+func (v *Vec3) Abs() float64 {
+	return abs32(v[:])
+}
+
+// Add provides the sum of two vectors
+func (v *Vec3) Add(w Vector) Vector {
+	r := &Vec3{}
+	add32(r[:], v[:], float32s("Vec3.Add", v, w))
+	return r
+}
+
+// Sub provides the difference of two vectors
+func (v *Vec3) Sub(w Vector) Vector {
+	r := &Vec3{}
+	sub32(r[:], v[:], float32s("Vec3.Sub", v, w))
+	return r
+}
+
+// Cbd provides the city-block-distance length of the vector as a float32
+func (v *Vec3) Cbd() interface{} {
+	return cbd32(v[:])
+}
+
+// Min provides a vector containing the smallest elements of both vectors
+func (v *Vec3) Min(w Vector) Vector {
+	r := &Vec3{}
+	min32(r[:], v[:], float32s("Vec3.Min", v, w))
+	return r
+}
+
+// MinD provides the index of the smallest value in the vector
+func (v *Vec3) MinD() int {
+	return minD32(v[:])
+}
+
+// Max provides a vector containing the largest elements of both vectors
+func (v *Vec3) Max(w Vector) Vector {
+	r := &Vec3{}
+	max32(r[:], v[:], float32s("Vec3.Max", v, w))
+	return r
+}
+
+// MaxD provides the index of the largest value in the vector
+func (v *Vec3) MaxD() int {
+	return maxD32(v[:])
+}
+
+// Cross provides the cross product of two 3D vectors, perpendicular to both
+func (v *Vec3) Cross(w Vector) Vector {
+	o := float32s("Vec3.Cross", v, w)
+	return &Vec3{
+		v[1]*o[2] - v[2]*o[1],
+		v[2]*o[0] - v[0]*o[2],
+		v[0]*o[1] - v[1]*o[0],
+	}
+}
+
+// Muls provides the vector multiplied by a float32
+func (v *Vec3) Muls(s interface{}) Vector {
+	f := scalar32("Vec3.Muls", s)
+	return &Vec3{v[0] * f, v[1] * f, v[2] * f}
+}
+
+// Divs provides the vector divided by a float32
+func (v *Vec3) Divs(s interface{}) Vector {
+	f := scalar32("Vec3.Divs", s)
+	return &Vec3{v[0] / f, v[1] / f, v[2] / f}
+}
+
+// Mulv provides the inner product of two vectors
+func (v *Vec3) Mulv(w Vector) float64 {
+	return mulv32(v[:], float32s("Vec3.Mulv", v, w))
+}
+
+// Kind is always Float32
+func (v *Vec3) Kind() reflect.Kind {
+	return reflect.Float32
+}
+
+// Len is always 3
+func (v *Vec3) Len() int {
+	return 3
+}
+
+// Get provides a value from the vector
+func (v *Vec3) Get(i int) interface{} {
+	return get32("Vec3.Get", v[:], i)
+}
+
+// Set changes a value in the vector
+func (v *Vec3) Set(i int, f interface{}) Vector {
+	set32("Vec3.Set", v[:], i, f)
+	return v
+}
+
+// Equal checks if two vectors are the same
+func (v *Vec3) Equal(w Vector) bool {
+	return equal32(v[:], float32s("Vec3.Equal", v, w))
+}
+
+// String implements the Stringer interface
+func (v *Vec3) String() string {
+	return string32(v[:])
+}
+
+// Unit provides a vector with length 1 in the direction of the vector
+func (v *Vec4) Unit() Vector {
+	return v.Divs(float32(v.Abs()))
+}
+
+// Abs provides the euclidian length of the vector
+func (v *Vec4) Abs() float64 {
+	return abs32(v[:])
+}
+
+// Add provides the sum of two vectors
+func (v *Vec4) Add(w Vector) Vector {
+	r := &Vec4{}
+	add32(r[:], v[:], float32s("Vec4.Add", v, w))
+	return r
+}
+
+// Sub provides the difference of two vectors
+func (v *Vec4) Sub(w Vector) Vector {
+	r := &Vec4{}
+	sub32(r[:], v[:], float32s("Vec4.Sub", v, w))
+	return r
+}
+
+// Cbd provides the city-block-distance length of the vector as a float32
+func (v *Vec4) Cbd() interface{} {
+	return cbd32(v[:])
+}
+
+// Min provides a vector containing the smallest elements of both vectors
+func (v *Vec4) Min(w Vector) Vector {
+	r := &Vec4{}
+	min32(r[:], v[:], float32s("Vec4.Min", v, w))
+	return r
+}
+
+// MinD provides the index of the smallest value in the vector
+func (v *Vec4) MinD() int {
+	return minD32(v[:])
+}
+
+// Max provides a vector containing the largest elements of both vectors
+func (v *Vec4) Max(w Vector) Vector {
+	r := &Vec4{}
+	max32(r[:], v[:], float32s("Vec4.Max", v, w))
+	return r
+}
+
+// MaxD provides the index of the largest value in the vector
+func (v *Vec4) MaxD() int {
+	return maxD32(v[:])
+}
+
+// Cross is only defined for 3D vectors
+func (v *Vec4) Cross(w Vector) Vector {
+	log.Fatalf("Vec4.Cross: expects 3D vectors, got 4D")
+	return nil
+}
+
+// Muls provides the vector multiplied by a float32
+func (v *Vec4) Muls(s interface{}) Vector {
+	f := scalar32("Vec4.Muls", s)
+	return &Vec4{v[0] * f, v[1] * f, v[2] * f, v[3] * f}
+}
+
+// Divs provides the vector divided by a float32
+func (v *Vec4) Divs(s interface{}) Vector {
+	f := scalar32("Vec4.Divs", s)
+	return &Vec4{v[0] / f, v[1] / f, v[2] / f, v[3] / f}
+}
+
+// Mulv provides the inner product of two vectors
+func (v *Vec4) Mulv(w Vector) float64 {
+	return mulv32(v[:], float32s("Vec4.Mulv", v, w))
+}
+
+// Kind is always Float32
+func (v *Vec4) Kind() reflect.Kind {
+	return reflect.Float32
+}
+
+// Len is always 4
+func (v *Vec4) Len() int {
+	return 4
+}
+
+// Get provides a value from the vector
+func (v *Vec4) Get(i int) interface{} {
+	return get32("Vec4.Get", v[:], i)
+}
+
+// Set changes a value in the vector
+func (v *Vec4) Set(i int, f interface{}) Vector {
+	set32("Vec4.Set", v[:], i, f)
+	return v
+}
+
+// Equal checks if two vectors are the same
+func (v *Vec4) Equal(w Vector) bool {
+	return equal32(v[:], float32s("Vec4.Equal", v, w))
+}
+
+// String implements the Stringer interface
+func (v *Vec4) String() string {
+	return string32(v[:])
+}
+
+// Float32s provides the values of a Float32 vector
+// For Vec3 and Vec4 these are the values themselves, not a copy: only read them.
+func Float32s(v Vector) []float32 {
+	switch v := v.(type) {
+	case *Vec3:
+		return v[:]
+	case *Vec4:
+		return v[:]
+	}
+	if v.Kind() != reflect.Float32 {
+		log.Fatalf("vector.Float32s: expects a Float32 vector, got %v", v.Kind())
+	}
+	values := make([]float32, v.Len())
+	for i := range values {
+		values[i] = v.Get(i).(float32)
+	}
+	return values
+}
+
+// fixedVector provides a Vec3 or Vec4 for float32 values of that length, or nil
+func fixedVector(values []float32) Vector {
+	switch len(values) {
+	case 3:
+		return &Vec3{values[0], values[1], values[2]}
+	case 4:
+		return &Vec4{values[0], values[1], values[2], values[3]}
+	}
+	return nil
+}
+
+// randomFixed provides a Vec3 or Vec4 filled like the generic random vector
+func randomFixed(dimension int) Vector {
+	values := make([]float32, dimension)
+	for i := range values {
+		values[i] = rand.Float32()
+	}
+	return fixedVector(values)
+}
+
+// float32s checks that w goes with v and provides its values
+func float32s(caller string, v Vector, w Vector) []float32 {
+	if w.Kind() != reflect.Float32 {
+		log.Fatalf("%s: kinds %v and %v do not match", caller, v.Kind(), w.Kind())
+	}
+	if w.Len() != v.Len() {
+		log.Fatalf("%s: dimensions %d and %d do not match", caller, v.Len(), w.Len())
+	}
+	return Float32s(w)
+}
+
+// scalar32 checks that s is a float32
+func scalar32(caller string, s interface{}) float32 {
+	f, ok := s.(float32)
+	if !ok {
+		log.Fatalf("%s: Scalar Type %v doesn't match vector type float32", caller, reflect.TypeOf(s))
+	}
+	return f
+}
+
+// abs32 provides the euclidian length of float32 values
+func abs32(v []float32) float64 {
+	l := 0.0
+	for _, f := range v {
+		l += math.Pow(float64(f), 2)
+	}
+	return math.Sqrt(l)
+}
+
+// add32 adds v and w into r
+func add32(r []float32, v []float32, w []float32) {
+	for i := range r {
+		r[i] = v[i] + w[i]
+	}
+}
+
+// sub32 subtracts w from v into r
+func sub32(r []float32, v []float32, w []float32) {
+	for i := range r {
+		r[i] = v[i] - w[i]
+	}
+}
+
+// cbd32 provides the sum of the absolute values, in float32 like the generic vector
+func cbd32(v []float32) float32 {
+	var r float32
+	for _, f := range v {
+		if f < 0.0 {
+			r -= f
+		} else {
+			r += f
+		}
+	}
+	return r
+}
+
+// min32 provides the smallest elements into r, taking the element of v unless w is smaller
+func min32(r []float32, v []float32, w []float32) {
+	for i := range r {
+		if w[i] < v[i] {
+			r[i] = w[i]
+		} else {
+			r[i] = v[i]
+		}
+	}
+}
+
+// max32 provides the largest elements into r, taking the element of v unless w is larger
+func max32(r []float32, v []float32, w []float32) {
+	for i := range r {
+		if v[i] < w[i] {
+			r[i] = w[i]
+		} else {
+			r[i] = v[i]
+		}
+	}
+}
+
+// minD32 provides the index of the first smallest value
+func minD32(v []float32) int {
+	r := 0
+	for i := range v {
+		if v[i] < v[r] {
+			r = i
+		}
+	}
+	return r
+}
+
+// maxD32 provides the index of the first largest value
+func maxD32(v []float32) int {
+	r := 0
+	for i := range v {
+		if v[r] < v[i] {
+			r = i
+		}
+	}
+	return r
+}
+
+// mulv32 provides the inner product of float32 values
+func mulv32(v []float32, w []float32) float64 {
+	r := 0.0
+	for i := range v {
+		r += float64(v[i]) * float64(w[i])
+	}
+	return r
+}
+
+// equal32 checks if all values are the same
+func equal32(v []float32, w []float32) bool {
+	for i := range v {
+		if v[i] != w[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// get32 provides a value, checking the index
+func get32(caller string, v []float32, i int) interface{} {
+	if i < 0 || i >= len(v) {
+		log.Fatalf("%s: Index %d out of bounds expected < %d", caller, i, len(v))
+	}
+	return v[i]
+}
+
+// set32 changes a value, checking the index and type
+func set32(caller string, v []float32, i int, f interface{}) {
+	if i < 0 || i >= len(v) {
+		log.Fatalf("%s: Index %d out of bounds expected < %d", caller, i, len(v))
+	}
+	value, ok := f.(float32)
+	if !ok {
+		log.Fatalf("%s: wrong value type %v expected float32", caller, reflect.TypeOf(f))
+	}
+	v[i] = value
+}
+
+// string32 formats float32 values like the generic vector
+func string32(v []float32) string {
+	var s strings.Builder
+
+	s.WriteString("[")
+	for i, f := range v {
+		if i > 0 {
+			s.WriteString(", ")
+		}
+		s.WriteString(fmt.Sprintf("%v", f))
+	}
+	s.WriteString("]")
+
+	return s.String()
+}
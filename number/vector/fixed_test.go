@@ -0,0 +1,69 @@
+package vector
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_FixedFactories(t *testing.T) {
+	if _, ok := NewVector([]float32{1.0, 2.0, 3.0}).(*Vec3); !ok {
+		t.Errorf("Expected a Vec3 for 3 float32 values")
+	}
+	if _, ok := NewVector([4]float32{}).(*Vec4); !ok {
+		t.Errorf("Expected a Vec4 for 4 float32 values")
+	}
+	if _, ok := ZeroVector(3, reflect.Float32).(*Vec3); !ok {
+		t.Errorf("Expected a Vec3 for a 3D Float32 zero vector")
+	}
+	if _, ok := RandomVector(4, reflect.Float32).(*Vec4); !ok {
+		t.Errorf("Expected a Vec4 for a 4D Float32 random vector")
+	}
+	if _, ok := NewVector([]float64{1.0, 2.0, 3.0}).(genericVector); !ok {
+		t.Errorf("Expected a generic vector for float64 values")
+	}
+}
+
+func Test_FixedOperations(t *testing.T) {
+	// Fixed and generic vectors give the same results and mix freely
+	fixed := NewVector([]float32{1.0, 2.0, 2.0})
+	generic := genericZeroVector(3, reflect.Float32)
+	generic.Set(0, float32(1.0)).Set(1, float32(2.0)).Set(2, float32(2.0))
+	other := NewVector([]float32{0.5, -1.0, 4.0})
+
+	if !fixed.Equal(generic) || !generic.Equal(fixed) || fixed.String() != generic.String() {
+		t.Errorf("Expected %v to equal %v", fixed, generic)
+	}
+	if fixed.Abs() != 3.0 || !fixed.Unit().Equal(generic.Unit()) {
+		t.Errorf("Expected length 3 and unit %v, got %v and %v", generic.Unit(), fixed.Abs(), fixed.Unit())
+	}
+	if !fixed.Add(other).Equal(generic.Add(other)) || !fixed.Sub(other).Equal(generic.Sub(other)) {
+		t.Errorf("Expected %v and %v, got %v and %v", generic.Add(other), generic.Sub(other), fixed.Add(other), fixed.Sub(other))
+	}
+	if fixed.Mulv(other) != generic.Mulv(other) || fixed.Mulv(other) != 6.5 {
+		t.Errorf("Expected 6.5, got %f", fixed.Mulv(other))
+	}
+	if !fixed.Muls(float32(2.0)).Equal(generic.Muls(float32(2.0))) || !fixed.Divs(float32(2.0)).Equal(generic.Divs(float32(2.0))) {
+		t.Errorf("Expected %v, got %v", generic.Muls(float32(2.0)), fixed.Muls(float32(2.0)))
+	}
+
+	// Set changes the vector itself, like it does for generic vectors
+	h := NewVector([]float32{0.0, 0.0, 0.0, 1.0})
+	h.Set(2, float32(5.0))
+	if h.Get(2).(float32) != 5.0 || h.Len() != 4 || h.Kind() != reflect.Float32 {
+		t.Errorf("Expected [0, 0, 5, 1], got %v", h)
+	}
+}
+
+func Test_FixedMinMax(t *testing.T) {
+	v := NewVector([]float32{-1.0, 4.0, 2.0})
+	w := NewVector([]float32{3.0, -2.0, 2.0})
+	if !v.Min(w).Equal(NewVector([]float32{-1.0, -2.0, 2.0})) || !v.Max(w).Equal(NewVector([]float32{3.0, 4.0, 2.0})) {
+		t.Errorf("Expected min and max of %v and %v, got %v and %v", v, w, v.Min(w), v.Max(w))
+	}
+	if v.MinD() != 0 || v.MaxD() != 1 || v.Cbd().(float32) != 7.0 {
+		t.Errorf("Expected 0, 1 and 7 for %v, got %d, %d and %v", v, v.MinD(), v.MaxD(), v.Cbd())
+	}
+	if h := NewVector([]float32{1.0, 1.0, 5.0, 5.0}); h.MaxD() != 2 {
+		t.Errorf("Expected the first largest value of %v, got %d", h, h.MaxD())
+	}
+}
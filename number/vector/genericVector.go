@@ -88,24 +88,38 @@ func genericRandomVector(dimension int, kind reflect.Kind) Vector {
 	return v
 }
 
-func genericNewVector(values interface{}) Vector {
-	k := reflect.TypeOf(values).Kind()
-	if k != reflect.Array && k != reflect.Slice {
-		log.Fatalf("genericNewVector: expected an array or slice, got %v", reflect.TypeOf(values).Kind())
+func genericNewVector(values interface{}) (Vector, error) {
+	if values == nil {
+		return nil, &Error{Op: "vector.NewVector", Err: ErrNotArray, Detail: "got nil"}
+	}
+	t := reflect.TypeOf(values)
+	if t.Kind() != reflect.Array && t.Kind() != reflect.Slice {
+		return nil, &Error{Op: "vector.NewVector", Err: ErrNotArray, Detail: fmt.Sprintf("got %v", t.Kind())}
 	}
 
 	source := reflect.ValueOf(values)
 	if source.Len() == 0 {
-		log.Fatalf("genericNewVector: cannot create from an empty array")
+		return nil, &Error{Op: "vector.NewVector", Err: ErrEmpty}
+	}
+	if !genericKind(t.Elem().Kind()) {
+		return nil, &Error{Op: "vector.NewVector", Err: ErrKind, Detail: fmt.Sprintf("got %v", t.Elem())}
 	}
 
-	// Fill with the content
-	v := genericZeroVector(source.Len(), source.Index(0).Kind())
+	// Fill with the content, named types like time.Duration don't fit the cells
+	v := genericZeroVector(source.Len(), t.Elem().Kind()).(genericVector)
+	if reflect.TypeOf(v.cells).Elem() != t.Elem() {
+		return nil, &Error{Op: "vector.NewVector", Err: ErrKind, Detail: fmt.Sprintf("got %v", t.Elem())}
+	}
 	for i := 0; i < v.Len(); i++ {
 		v.Set(i, source.Index(i).Interface())
 	}
 
-	return v
+	return v, nil
+}
+
+// genericKind checks if there is a generic vector for the kind
+func genericKind(kind reflect.Kind) bool {
+	return (kind >= reflect.Int && kind <= reflect.Uint64) || kind == reflect.Float32 || kind == reflect.Float64
 }
 
 // Unit provides a vector with length 1 in the direction of the vector
@@ -163,17 +177,30 @@ func (v genericVector) Abs() float64 {
 	return math.Sqrt(l)
 }
 
-// Cbd provides the city-block-distance length of a vector
-// func (v genericVector) Cbd() interface{} {
-// 	var l float64
-
-// 	for i := 0; i < v.Len(); i++ {
-// 		l += math.Abs(float64(v.Get(i)))
-// 	}
-
-// 	// TODO: the return value should be the same type as the vector?
-// 	return l
-// }
+// Cbd provides the city-block-distance length of a vector, the sum of the absolute values
+// The result has the type of the elements, so integers wrap arround like they do in Add.
+func (v genericVector) Cbd() interface{} {
+	source := reflect.ValueOf(v.cells)
+	var i64 int64
+	var u64 uint64
+	var f64 float64
+	for i := 0; i < v.Len(); i++ {
+		value := source.Index(i)
+		switch {
+		case isSigned(v.kind):
+			if value.Int() < 0 {
+				i64 -= value.Int()
+			} else {
+				i64 += value.Int()
+			}
+		case isUnsigned(v.kind):
+			u64 += value.Uint()
+		default:
+			f64 = v.round(f64 + math.Abs(value.Float()))
+		}
+	}
+	return v.scalar(i64, u64, f64)
+}
 
 // Add substracts one vector from another
 func (v genericVector) Add(w Vector) Vector {
@@ -264,60 +291,147 @@ func (v genericVector) Sub(w Vector) Vector {
 }
 
 // Min provides a vector containing the smallest elements of both vectors
-// func (v genericVector) Min(w Vector) Vector {
-// 	if v.Len() != w.Len() {
-// 		log.Fatalf("Vector.Min: dimensions of vectors must be the same")
-// 	}
+// Where the elements are equal or can't be compared, like NaN, the element of v is used.
+func (v genericVector) Min(w Vector) Vector {
+	v.compatible("genericVector.Min", w)
 
-// 	r := Zero(v.Len())
-// 	for i := 0; i < r.Len(); i++ {
-// 		r = r.Set(i, math.Min(v.Get(i).(float64), w.Get(i).(float64)))
-// 	}
+	r := genericZeroVector(v.Len(), v.Kind())
+	for i := 0; i < r.Len(); i++ {
+		if v.less(w.Get(i), v.Get(i)) {
+			r.Set(i, w.Get(i))
+		} else {
+			r.Set(i, v.Get(i))
+		}
+	}
 
-// 	return r
-// }
+	return r
+}
 
 // MinD provides the index of the smallest value in the vector
-// func (v genericVector) MinD() int {
+// It provides the first occurence if there are more dimensions with this value
+func (v genericVector) MinD() int {
 
-// 	r := 0
-// 	for i := 0; i < v.Len(); i++ {
-// 		if v.Get(i).(float64) < v.Get(r).(float64) {
-// 			r = i
-// 		}
-// 	}
+	r := 0
+	for i := 1; i < v.Len(); i++ {
+		if v.less(v.Get(i), v.Get(r)) {
+			r = i
+		}
+	}
 
-// 	return r
-// }
+	return r
+}
 
 // Max set every element of the resulting vector to the highest option
-// It provides the first occurence if there are more dimensions with this value
-// func (v genericVector) Max(w Vector) Vector {
-// 	if v.Len() != w.Len() {
-// 		log.Fatalf("Vector.Max: dimensions of vectors must be the same")
-// 	}
+// Where the elements are equal or can't be compared, like NaN, the element of v is used.
+func (v genericVector) Max(w Vector) Vector {
+	v.compatible("genericVector.Max", w)
 
-// 	r := Zero(v.Len())
-// 	for i := 0; i < r.Len(); i++ {
-// 		r = r.Set(i, math.Max(v.Get(i).(float64), w.Get(i).(float64)))
-// 	}
+	r := genericZeroVector(v.Len(), v.Kind())
+	for i := 0; i < r.Len(); i++ {
+		if v.less(v.Get(i), w.Get(i)) {
+			r.Set(i, w.Get(i))
+		} else {
+			r.Set(i, v.Get(i))
+		}
+	}
 
-// 	return r
-// }
+	return r
+}
 
 // MaxD provides the index of the largest value in the vector
 // It provides the first occurence if there are more dimensions with this value
-// func (v genericVector) MaxD() int {
+func (v genericVector) MaxD() int {
 
-// 	r := 0
-// 	for i := 0; i < v.Len(); i++ {
-// 		if v.Get(i).(float64) > v.Get(r).(float64) {
-// 			r = i
-// 		}
-// 	}
+	r := 0
+	for i := 1; i < v.Len(); i++ {
+		if v.less(v.Get(r), v.Get(i)) {
+			r = i
+		}
+	}
+
+	return r
+}
+
+// Cross provides the cross product of two 3D vectors, perpendicular to both
+// Integers wrap arround like they do in Add, Float32 is rounded after every step like it
+// would be in plain float32 arithmetic.
+func (v genericVector) Cross(w Vector) Vector {
+	v.compatible("genericVector.Cross", w)
+	if v.Len() != 3 {
+		log.Fatalf("genericVector.Cross: expects 3D vectors, got %dD", v.Len())
+	}
+
+	a := [3]reflect.Value{reflect.ValueOf(v.Get(0)), reflect.ValueOf(v.Get(1)), reflect.ValueOf(v.Get(2))}
+	b := [3]reflect.Value{reflect.ValueOf(w.Get(0)), reflect.ValueOf(w.Get(1)), reflect.ValueOf(w.Get(2))}
+	r := genericZeroVector(3, v.Kind())
+	for i := 0; i < 3; i++ {
+		j, k := (i+1)%3, (i+2)%3
+		switch {
+		case isSigned(v.kind):
+			r.Set(i, v.scalar(a[j].Int()*b[k].Int()-a[k].Int()*b[j].Int(), 0, 0))
+		case isUnsigned(v.kind):
+			r.Set(i, v.scalar(0, a[j].Uint()*b[k].Uint()-a[k].Uint()*b[j].Uint(), 0))
+		default:
+			r.Set(i, v.scalar(0, 0, v.round(v.round(a[j].Float()*b[k].Float())-v.round(a[k].Float()*b[j].Float()))))
+		}
+	}
+
+	return r
+}
 
-// 	return r
-// }
+// compatible stops on vectors of another kind or dimension
+func (v genericVector) compatible(caller string, w Vector) {
+	if w.Kind() != v.Kind() {
+		log.Fatalf("%s: kinds %v and %v do not match", caller, v.Kind(), w.Kind())
+	}
+	if w.Len() != v.Len() {
+		log.Fatalf("%s: dimensions %d and %d do not match", caller, v.Len(), w.Len())
+	}
+}
+
+// less compares two elements of the kind of the vector
+func (v genericVector) less(a interface{}, b interface{}) bool {
+	x, y := reflect.ValueOf(a), reflect.ValueOf(b)
+	switch {
+	case isSigned(v.kind):
+		return x.Int() < y.Int()
+	case isUnsigned(v.kind):
+		return x.Uint() < y.Uint()
+	}
+	return x.Float() < y.Float()
+}
+
+// round rounds a float64 result to Float32 precision for Float32 vectors
+func (v genericVector) round(f float64) float64 {
+	if v.kind == reflect.Float32 {
+		return float64(float32(f))
+	}
+	return f
+}
+
+// scalar converts a result into the kind of the vector, using the one that fits the kind
+// Converting to a smaller integer keeps the lower bits, which is where arithmetic on the
+// smaller integer itself would have ended up.
+func (v genericVector) scalar(i64 int64, u64 uint64, f64 float64) interface{} {
+	t := reflect.TypeOf(v.cells).Elem()
+	switch {
+	case isSigned(v.kind):
+		return reflect.ValueOf(i64).Convert(t).Interface()
+	case isUnsigned(v.kind):
+		return reflect.ValueOf(u64).Convert(t).Interface()
+	}
+	return reflect.ValueOf(f64).Convert(t).Interface()
+}
+
+// isSigned tells if the kind is a signed integer
+func isSigned(kind reflect.Kind) bool {
+	return kind >= reflect.Int && kind <= reflect.Int64
+}
+
+// isUnsigned tells if the kind is an unsigned integer
+func isUnsigned(kind reflect.Kind) bool {
+	return kind >= reflect.Uint && kind <= reflect.Uint64
+}
 
 // Muls multiplies a vector by a scalar.
 // Divs divides a vector by a scalar.
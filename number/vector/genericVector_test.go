@@ -1,6 +1,7 @@
 package vector
 
 import (
+	"errors"
 	"reflect"
 	"testing"
 )
@@ -29,6 +30,31 @@ func Test_NewVector(t *testing.T) {
 	}
 }
 
+func Test_TryNewVector(t *testing.T) {
+	type meters float32
+	tests := []struct {
+		values   interface{}
+		expected error
+	}{
+		{nil, ErrNotArray},
+		{3, ErrNotArray},
+		{[]float32{}, ErrEmpty},
+		{[]string{"x"}, ErrKind},
+		{[]interface{}{1.0}, ErrKind},
+		{[]meters{1.0}, ErrKind},
+	}
+	for i, test := range tests {
+		v, err := TryNewVector(test.values)
+		var e *Error
+		if v != nil || !errors.Is(err, test.expected) || !errors.As(err, &e) || e.Op != "vector.NewVector" {
+			t.Errorf("Test %d: expected %v, got %v %v", i, test.expected, v, err)
+		}
+	}
+	if v, err := TryNewVector([2]uint8{1, 2}); err != nil || v.Get(1).(uint8) != 2 {
+		t.Errorf("Expected [1, 2], got %v %v", v, err)
+	}
+}
+
 func Test_GenericUnit(t *testing.T) {
 	// See if we get '1.0' for each of the axis
 	v1 := NewVector([]float32{4.0, 0.0, 0.0})
@@ -103,3 +129,57 @@ func Test_GenericEqual(t *testing.T) {
 	}
 
 }
+
+func Test_GenericMinMax(t *testing.T) {
+	v := NewVector([]int8{-3, 5, 2, 5})
+	w := NewVector([]int8{1, -7, 2, 9})
+	if r := v.Min(w); !r.Equal(NewVector([]int8{-3, -7, 2, 5})) {
+		t.Errorf("min(%v, %v) --> %v", v, w, r)
+	}
+	if r := v.Max(w); !r.Equal(NewVector([]int8{1, 5, 2, 9})) {
+		t.Errorf("max(%v, %v) --> %v", v, w, r)
+	}
+	if v.MinD() != 0 || v.MaxD() != 1 {
+		t.Errorf("Expected 0 and 1 as smallest and first largest, got %d and %d", v.MinD(), v.MaxD())
+	}
+
+	// Unsigned values compare without a sign
+	u := NewVector([]uint64{1 << 63, 1})
+	if u.MaxD() != 0 || u.Min(NewVector([]uint64{2, 2})).Get(0).(uint64) != 2 {
+		t.Errorf("Expected %v to have its largest value first", u)
+	}
+}
+
+func Test_GenericCbd(t *testing.T) {
+	if cbd := NewVector([]int{-1, 2, -3}).Cbd(); cbd != 6 {
+		t.Errorf("Expected 6, got %v", cbd)
+	}
+	if cbd := NewVector([]float64{-1.5, 0.5}).Cbd(); cbd != 2.0 {
+		t.Errorf("Expected 2.0, got %v", cbd)
+	}
+	if cbd := NewVector([]uint8{200, 100}).Cbd(); cbd != uint8(44) {
+		t.Errorf("Expected uint8 44 after wrapping, got %v", cbd)
+	}
+}
+
+func Test_GenericCross(t *testing.T) {
+	x := NewVector([]int{1, 0, 0})
+	y := NewVector([]int{0, 1, 0})
+	if z := x.Cross(y); !z.Equal(NewVector([]int{0, 0, 1})) {
+		t.Errorf("x × y --> %v, expected [0, 0, 1]", z)
+	}
+	if z := y.Cross(x); !z.Equal(NewVector([]int{0, 0, -1})) {
+		t.Errorf("y × x --> %v, expected [0, 0, -1]", z)
+	}
+
+	// Float32 is calculated like plain float32 arithmetic, so generic and fixed vectors agree
+	a := []float32{0.1, 0.7, -1.3}
+	b := []float32{2.9, -0.3, 0.6}
+	generic := genericZeroVector(3, reflect.Float32)
+	for i, f := range a {
+		generic.Set(i, f)
+	}
+	if r := generic.Cross(NewVector(b)); !r.Equal(NewVector(a).Cross(NewVector(b))) {
+		t.Errorf("Expected %v, got %v", NewVector(a).Cross(NewVector(b)), r)
+	}
+}
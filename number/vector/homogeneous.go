@@ -0,0 +1,56 @@
+package vector
+
+import (
+	"log"
+	"reflect"
+)
+
+// Homogeneous extends a point with w = 1, so 4x4 matrices can move it arround
+// It works for Float32 and Float64 vectors of any dimension.
+func Homogeneous(point Vector) Vector {
+	if p, ok := point.(*Vec3); ok {
+		return &Vec4{p[0], p[1], p[2], 1.0}
+	}
+
+	switch point.Kind() {
+	case reflect.Float32:
+		return NewVector(append(Float32s(point)[:point.Len():point.Len()], 1.0))
+	case reflect.Float64:
+		values := make([]float64, point.Len(), point.Len()+1)
+		for i := range values {
+			values[i] = point.Get(i).(float64)
+		}
+		return NewVector(append(values, 1.0))
+	}
+	log.Fatalf("vector.Homogeneous: only supported for Float32 and Float64 vectors, got %v", point.Kind())
+	return nil
+}
+
+// PerspectiveDivide turns homogeneous coordinates back into a point by dividing by w, the last value
+// A w of 0 is a direction rather than a point, it ends up in infinity.
+func PerspectiveDivide(point Vector) Vector {
+	if point.Len() < 2 {
+		log.Fatalf("vector.PerspectiveDivide: expects at least 2D vector, got %dD", point.Len())
+	}
+
+	last := point.Len() - 1
+	switch point.Kind() {
+	case reflect.Float32:
+		values := Float32s(point)
+		w := values[last]
+		result := make([]float32, last)
+		for i := range result {
+			result[i] = values[i] / w
+		}
+		return NewVector(result)
+	case reflect.Float64:
+		w := point.Get(last).(float64)
+		result := make([]float64, last)
+		for i := range result {
+			result[i] = point.Get(i).(float64) / w
+		}
+		return NewVector(result)
+	}
+	log.Fatalf("vector.PerspectiveDivide: only supported for Float32 and Float64 vectors, got %v", point.Kind())
+	return nil
+}
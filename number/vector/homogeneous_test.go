@@ -0,0 +1,28 @@
+package vector
+
+import "testing"
+
+func Test_Homogeneous(t *testing.T) {
+	if h := Homogeneous(NewVector([]float32{1.0, 2.0, 3.0})); !h.Equal(NewVector([]float32{1.0, 2.0, 3.0, 1.0})) {
+		t.Errorf("Expected [1, 2, 3, 1], got %v", h)
+	}
+	if h := Homogeneous(NewVector([]float64{4.0, 5.0})); !h.Equal(NewVector([]float64{4.0, 5.0, 1.0})) {
+		t.Errorf("Expected [4, 5, 1], got %v", h)
+	}
+
+	// Extending doesn't change the point itself
+	point := NewVector([]float32{1.0, 2.0, 3.0, 4.0, 5.0})
+	Homogeneous(point).Set(5, float32(2.0))
+	if point.Len() != 5 {
+		t.Errorf("Expected the point to stay the same, got %v", point)
+	}
+}
+
+func Test_PerspectiveDivide(t *testing.T) {
+	if p := PerspectiveDivide(NewVector([]float32{2.0, 4.0, 6.0, 2.0})); !p.Equal(NewVector([]float32{1.0, 2.0, 3.0})) {
+		t.Errorf("Expected [1, 2, 3], got %v", p)
+	}
+	if p := PerspectiveDivide(NewVector([]float64{3.0, -0.5})); !p.Equal(NewVector([]float64{-6.0})) {
+		t.Errorf("Expected [-6], got %v", p)
+	}
+}
@@ -2,6 +2,7 @@ package vector
 
 import (
 	"fmt"
+	"log"
 	"reflect"
 )
 
@@ -9,13 +10,14 @@ import (
 type Vector interface {
 	Unit() Vector
 	Abs() float64
-	//	Cbd() interface{}
+	Cbd() interface{}
 	Add(w Vector) Vector
 	Sub(w Vector) Vector
-	//	Min(w Vector) Vector
-	//	MinD() int
-	//	Max(w Vector) Vector
-	//	MaxD() int
+	Min(w Vector) Vector
+	MinD() int
+	Max(w Vector) Vector
+	MaxD() int
+	Cross(w Vector) Vector
 	Muls(s interface{}) Vector
 	Divs(s interface{}) Vector
 	Mulv(w Vector) float64
@@ -29,24 +31,41 @@ type Vector interface {
 
 // ZeroVector creates a vector of the requested size set to the origin
 func ZeroVector(dimension int, kind reflect.Kind) Vector {
-	//	if dimension == 3 {
-	//		return zero3D()
-	//	}
+	if kind == reflect.Float32 && (dimension == 3 || dimension == 4) {
+		return fixedVector(make([]float32, dimension))
+	}
 	return genericZeroVector(dimension, kind)
 }
 
 // RandomVector creates a vector of the requested size set to a random location
 func RandomVector(dimension int, kind reflect.Kind) Vector {
-	//	if dimension == 3 {
-	//		return rand3D()
-	//	}
+	if kind == reflect.Float32 && (dimension == 3 || dimension == 4) {
+		return randomFixed(dimension)
+	}
 	return genericRandomVector(dimension, kind)
 }
 
 // NewVector creates a vector based on a list of values
 func NewVector(f interface{}) Vector {
-	//	if len(f) == 3 {
-	//		return make3D(f)
-	//	}
+	v, err := TryNewVector(f)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return v
+}
+
+// TryNewVector creates a vector based on a list of values, like NewVector, but reports
+// a *Error instead of stopping when the values don't make a vector
+func TryNewVector(f interface{}) (Vector, error) {
+	switch values := f.(type) {
+	case []float32:
+		if v := fixedVector(values); v != nil {
+			return v, nil
+		}
+	case [3]float32:
+		return &Vec3{values[0], values[1], values[2]}, nil
+	case [4]float32:
+		return &Vec4{values[0], values[1], values[2], values[3]}, nil
+	}
 	return genericNewVector(f)
 }
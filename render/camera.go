@@ -0,0 +1,191 @@
+package render
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+
+	"../number/matrix"
+	"../number/vector"
+)
+
+// Camera defines the point of view used to project the model onto the canvas
+type Camera struct {
+	position vector.Vector
+	lookat   vector.Vector
+	up       vector.Vector
+	fov      float32 // vertical field of view in degrees
+	aspect   float32 // width / height of the view
+	near     float32 // distance to the near clipping plane
+	far      float32 // distance to the far clipping plane
+
+	projection   Projection
+	viewHeight   float32 // height of the orthographic view volume, 0 follows the field of view
+	oblique      float32 // foreshortening of the receding axis, 0 for no oblique projection
+	obliqueAngle float32 // angle of the receding axis in degrees
+}
+
+// NewCamera creates a perspective camera at position looking at lookat
+// The camera starts with the z-axis as up, a 60deg field of view,
+// a square aspect ratio and sees everything between 1 and 1000 units away
+func NewCamera(postion vector.Vector, lookat vector.Vector) *Camera {
+	return &Camera{
+		position: postion,
+		lookat:   lookat,
+		up:       vector.NewVector([]float32{0.0, 0.0, 1.0}),
+		fov:      60.0,
+		aspect:   1.0,
+		near:     1.0,
+		far:      1000.0,
+
+		projection: Perspective,
+	}
+}
+
+// SetPosition moves the camera
+func (c *Camera) SetPosition(position vector.Vector) {
+	if position.Len() != 3 || position.Kind() != reflect.Float32 {
+		log.Fatalf("Camera.SetPosition: expects 3D-Float32 vector, got %dD-%v", position.Len(), position.Kind())
+	}
+	c.position = position
+}
+
+// SetLookat changes the point the camera looks at
+func (c *Camera) SetLookat(lookat vector.Vector) {
+	if lookat.Len() != 3 || lookat.Kind() != reflect.Float32 {
+		log.Fatalf("Camera.SetLookat: expects 3D-Float32 vector, got %dD-%v", lookat.Len(), lookat.Kind())
+	}
+	c.lookat = lookat
+}
+
+// SetUp changes the direction that shows up as 'up' on the canvas
+// It does not have to be perpendicular to the line of sight
+func (c *Camera) SetUp(up vector.Vector) {
+	if up.Len() != 3 || up.Kind() != reflect.Float32 {
+		log.Fatalf("Camera.SetUp: expects 3D-Float32 vector, got %dD-%v", up.Len(), up.Kind())
+	}
+	c.up = up
+}
+
+// SetFieldOfView sets the vertical field of view in degrees
+func (c *Camera) SetFieldOfView(fov float32) {
+	if fov <= 0.0 || fov >= 180.0 {
+		log.Fatalf("Camera.SetFieldOfView: expects (0..180) degrees, got %f", fov)
+	}
+	c.fov = fov
+}
+
+// SetAspect sets the width / height ratio of the view, typically that of the canvas
+func (c *Camera) SetAspect(aspect float32) {
+	if aspect <= 0.0 {
+		log.Fatalf("Camera.SetAspect: expects a positive ratio, got %f", aspect)
+	}
+	c.aspect = aspect
+}
+
+// SetPlanes sets the distances of the near and far clipping planes
+func (c *Camera) SetPlanes(near float32, far float32) {
+	if near <= 0.0 || far <= near {
+		log.Fatalf("Camera.SetPlanes: expects 0 < near < far, got (n:%f, f:%f)", near, far)
+	}
+	c.near = near
+	c.far = far
+}
+
+// ViewMatrix provides the 4x4 matrix that moves world coordinates into camera coordinates
+// In camera coordinates the camera sits in the origin, looks down the negative z-axis and
+// has the y-axis pointing up
+func (c *Camera) ViewMatrix() matrix.Matrix {
+	if c.position.Equal(c.lookat) {
+		log.Fatalf("Camera.ViewMatrix: Camera position is the same as camera lookat")
+	}
+
+	return matrix.LookAt(c.position, c.lookat, c.up)
+}
+
+// ProjectionMatrix provides the 4x4 matrix that moves camera coordinates into clip space
+// After the perspective divide everything within the view volume ends up in [-1..1] on all axis
+func (c *Camera) ProjectionMatrix() matrix.Matrix {
+	if c.projection == Orthographic {
+		return c.orthographicMatrix()
+	}
+
+	return matrix.Perspective(c.fov, c.aspect, c.near, c.far)
+}
+
+// Matrix provides the combined view and projection matrix that moves world coordinates into clip space
+func (c *Camera) Matrix() matrix.Matrix {
+	return c.ProjectionMatrix().Mulm(c.ViewMatrix())
+}
+
+// Transform translates a point into homogeneous clip space (x, y, z, w)
+// A point is inside the view volume if -w <= x, y, z <= w, which also holds for points
+// behind the camera as long as they are not in view: clipping is done on these coordinates.
+func (c *Camera) Transform(point vector.Vector) vector.Vector {
+	return c.Matrix().Mulv(vector.Homogeneous(point))
+}
+
+// Project translates a point into the view of the camera
+// The new vector represents values between ([0..1], [0..1], depth) where (0, 0) is the
+// bottom-left corner of the view and depth runs from 0 on the near plane to 1 on the far plane.
+// Points outside the view end up outside these ranges, points behind the camera have no
+// projection and stop the program: use TryProject, or Transform and clip those first.
+func (c *Camera) Project(point vector.Vector) vector.Vector {
+	projected, err := c.TryProject(point)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return projected
+}
+
+// TryProject translates a point into the view of the camera like Project, but reports a
+// *Error instead of stopping, also for points on or behind the plane of the camera
+func (c *Camera) TryProject(point vector.Vector) (vector.Vector, error) {
+	if point == nil || point.Len() != 3 || point.Kind() != reflect.Float32 {
+		return nil, &Error{Op: "Camera.Project", Err: ErrDimension, Detail: fmt.Sprintf("got %v", point)}
+	}
+	if c.position.Equal(c.lookat) {
+		return nil, &Error{Op: "Camera.Project", Err: ErrCameraOnLookat}
+	}
+
+	clip := c.Transform(point)
+	if w := clip.Get(3).(float32); w <= 0.0 {
+		return nil, &Error{Op: "Camera.Project", Err: ErrBehindCamera, Detail: fmt.Sprintf("got w %f", w)}
+	}
+	return normalize(clip), nil
+}
+
+// Unproject translates a point in the view of the camera back into world coordinates
+// It undoes Project: (0, 0) is the bottom-left corner of the view and depth runs from 0 on the
+// near plane to 1 on the far plane.
+func (c *Camera) Unproject(point vector.Vector) (vector.Vector, error) {
+	if point == nil || point.Len() != 3 || point.Kind() != reflect.Float32 {
+		return nil, &Error{Op: "Camera.Unproject", Err: ErrDimension, Detail: fmt.Sprintf("got %v", point)}
+	}
+	if c.position.Equal(c.lookat) {
+		return nil, &Error{Op: "Camera.Unproject", Err: ErrCameraOnLookat}
+	}
+
+	inverse, err := matrix.Inverse(c.Matrix())
+	if err != nil {
+		return nil, err
+	}
+	ndc := point.Muls(float32(2.0)).Sub(vector.NewVector([]float32{1.0, 1.0, 1.0}))
+	return vector.PerspectiveDivide(inverse.Mulv(vector.Homogeneous(ndc))), nil
+}
+
+// sight provides the unit direction from position towards the viewer
+func (c *Camera) sight(position vector.Vector) vector.Vector {
+	toCamera := c.position.Sub(position)
+	if c.projection == Orthographic || toCamera.Abs() == 0.0 {
+		return c.position.Sub(c.lookat).Unit()
+	}
+	return toCamera.Unit()
+}
+
+// normalize does the perspective divide on a point in clip space and maps the
+// normalized device coordinates from [-1..1] onto [0..1], any attributes after w are dropped
+func normalize(clip vector.Vector) vector.Vector {
+	ndc := vector.PerspectiveDivide(vector.NewVector(vector.Float32s(clip)[:4]))
+	return ndc.Add(vector.NewVector([]float32{1.0, 1.0, 1.0})).Divs(float32(2.0))
+}
@@ -0,0 +1,86 @@
+package render
+
+import (
+	"../number/vector"
+)
+
+// The six planes of the view volume in clip space, a point p is inside a plane when
+// plane·p >= 0, so -w <= x <= w becomes x + w >= 0 and w - x >= 0 and so on
+var clipPlanes = [6][4]float32{
+	{1.0, 0.0, 0.0, 1.0},  // left
+	{-1.0, 0.0, 0.0, 1.0}, // right
+	{0.0, 1.0, 0.0, 1.0},  // bottom
+	{0.0, -1.0, 0.0, 1.0}, // top
+	{0.0, 0.0, 1.0, 1.0},  // near
+	{0.0, 0.0, -1.0, 1.0}, // far
+}
+
+// distance gives the signed distance of a point in clip space to a clipping plane
+// Only the first four elements are used, anything after that is carried along as attributes
+func distance(plane [4]float32, point vector.Vector) float32 {
+	return plane[0]*point.Get(0).(float32) + plane[1]*point.Get(1).(float32) +
+		plane[2]*point.Get(2).(float32) + plane[3]*point.Get(3).(float32)
+}
+
+// lerp interpolates between two points, t = 0 gives a and t = 1 gives b
+func lerp(a vector.Vector, b vector.Vector, t float32) vector.Vector {
+	return a.Add(b.Sub(a).Muls(t))
+}
+
+// clipPolygon clips a convex polygon in clip space against the view volume (Sutherland-Hodgman)
+// The result is a convex polygon again, with fewer than three points if nothing is in view.
+// The points must start with x, y, z, w, any further elements are interpolated along.
+func clipPolygon(polygon []vector.Vector) []vector.Vector {
+	for _, plane := range clipPlanes {
+		if len(polygon) == 0 {
+			break
+		}
+
+		result := make([]vector.Vector, 0, len(polygon)+1)
+		for i, current := range polygon {
+			next := polygon[(i+1)%len(polygon)]
+			dc := distance(plane, current)
+			dn := distance(plane, next)
+
+			if dc >= 0.0 {
+				result = append(result, current)
+			}
+			if (dc >= 0.0) != (dn >= 0.0) {
+				result = append(result, lerp(current, next, dc/(dc-dn)))
+			}
+		}
+		polygon = result
+	}
+
+	return polygon
+}
+
+// clipLine clips a line in clip space against the view volume (Liang-Barsky)
+// It reports if any part of the line is in view
+func clipLine(from vector.Vector, to vector.Vector) (vector.Vector, vector.Vector, bool) {
+	t0, t1 := float32(0.0), float32(1.0)
+	for _, plane := range clipPlanes {
+		df := distance(plane, from)
+		dt := distance(plane, to)
+
+		switch {
+		case df < 0.0 && dt < 0.0:
+			return from, to, false
+		case df < 0.0:
+			t := df / (df - dt)
+			if t > t0 {
+				t0 = t
+			}
+		case dt < 0.0:
+			t := df / (df - dt)
+			if t < t1 {
+				t1 = t
+			}
+		}
+	}
+	if t0 > t1 {
+		return from, to, false
+	}
+
+	return lerp(from, to, t0), lerp(from, to, t1), true
+}
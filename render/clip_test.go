@@ -0,0 +1,73 @@
+package render
+
+import (
+	"testing"
+
+	"../number/vector"
+)
+
+func Test_ClipPolygon(t *testing.T) {
+	// Completely inside stays the same
+	inside := []vector.Vector{
+		vector.NewVector([]float32{-0.5, -0.5, 0.0, 1.0}),
+		vector.NewVector([]float32{0.5, -0.5, 0.0, 1.0}),
+		vector.NewVector([]float32{0.0, 0.5, 0.0, 1.0}),
+	}
+	if r := clipPolygon(inside); len(r) != 3 {
+		t.Errorf("Expected 3 points, got %v", r)
+	}
+
+	// Completely outside disappears
+	outside := []vector.Vector{
+		vector.NewVector([]float32{2.0, 2.0, 0.0, 1.0}),
+		vector.NewVector([]float32{3.0, 2.0, 0.0, 1.0}),
+		vector.NewVector([]float32{2.0, 3.0, 0.0, 1.0}),
+	}
+	if r := clipPolygon(outside); len(r) != 0 {
+		t.Errorf("Expected nothing, got %v", r)
+	}
+
+	// One point behind the camera (negative w) cuts off a corner, making it a quad
+	// Attributes after w are interpolated along
+	behind := []vector.Vector{
+		vector.NewVector([]float32{0.0, 0.0, -2.0, -1.0, 1.0}),
+		vector.NewVector([]float32{-0.5, 0.0, 0.0, 1.0, 0.0}),
+		vector.NewVector([]float32{0.5, 0.0, 0.0, 1.0, 0.0}),
+	}
+	r := clipPolygon(behind)
+	if len(r) != 4 {
+		t.Fatalf("Expected 4 points, got %v", r)
+	}
+	for _, p := range r {
+		w := p.Get(3).(float32)
+		for i := 0; i < 3; i++ {
+			if c := p.Get(i).(float32); c < -w-1e-5 || c > w+1e-5 {
+				t.Errorf("Expected %v inside the view volume", p)
+			}
+		}
+		if a := p.Get(4).(float32); a < 0.0 || a > 1.0 {
+			t.Errorf("Expected attribute in [0..1], got %v", p)
+		}
+	}
+}
+
+func Test_ClipLine(t *testing.T) {
+	// A line from the center to behind the camera is cut at the near plane
+	from := vector.NewVector([]float32{0.0, 0.0, 0.0, 1.0})
+	to := vector.NewVector([]float32{0.0, 0.0, -3.0, -1.0})
+	f, e, visible := clipLine(from, to)
+	if !visible || !f.Equal(from) {
+		t.Fatalf("Expected the line to start at %v, got %v", from, f)
+	}
+	if e.Get(2).(float32) < -e.Get(3).(float32)-1e-5 {
+		t.Errorf("Expected the end %v on the near plane", e)
+	}
+
+	// A line next to the view isn't visible at all
+	_, _, visible = clipLine(
+		vector.NewVector([]float32{2.0, 0.0, 0.0, 1.0}),
+		vector.NewVector([]float32{2.0, 1.0, 0.0, 1.0}))
+	if visible {
+		t.Errorf("Expected the line to be out of view")
+	}
+}
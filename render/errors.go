@@ -0,0 +1,17 @@
+package render
+
+import (
+	"errors"
+
+	"../internal/failure"
+)
+
+// The reasons the camera can't project a point, check for them with errors.Is
+var (
+	ErrDimension      = errors.New("expects 3D-Float32 vector")
+	ErrCameraOnLookat = errors.New("camera position is the same as camera lookat")
+	ErrBehindCamera   = errors.New("point is not in front of the camera")
+)
+
+// Error tells which projection failed and why, get it with errors.As
+type Error = failure.Error
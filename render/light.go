@@ -0,0 +1,146 @@
+package render
+
+import (
+	"image/color"
+	"log"
+	"math"
+	"reflect"
+
+	"../number/vector"
+)
+
+// Light is a source of light shining on the model
+type Light interface {
+	// Illuminate provides the light arriving at position as an rgb intensity and the unit
+	// direction from position towards the light. Ambient light doesn't come from any
+	// particular direction and has a nil direction.
+	Illuminate(position vector.Vector) (intensity vector.Vector, direction vector.Vector)
+}
+
+// ambientLight lights everything equally from all directions
+type ambientLight struct {
+	intensity vector.Vector
+}
+
+// directionalLight shines in one direction from infinitely far away, like the sun
+type directionalLight struct {
+	intensity vector.Vector
+	toLight   vector.Vector
+}
+
+// pointLight shines in all directions from a single position, dimming with distance
+type pointLight struct {
+	intensity   vector.Vector
+	position    vector.Vector
+	attenuation vector.Vector // constant, linear and quadratic
+}
+
+// spotLight is a point light limited to a cone
+type spotLight struct {
+	pointLight
+	direction vector.Vector
+	cosInner  float64
+	cosOuter  float64
+}
+
+// NewAmbientLight creates a light that lights everything equally
+func NewAmbientLight(color color.Color, strength float32) Light {
+	return ambientLight{rgb(color, strength)}
+}
+
+// NewDirectionalLight creates a light that shines in direction from infinitely far away
+func NewDirectionalLight(direction vector.Vector, color color.Color, strength float32) Light {
+	checkVector("NewDirectionalLight", direction)
+	if direction.Abs() == 0.0 {
+		log.Fatalf("Render.NewDirectionalLight: direction can't be zero")
+	}
+	return directionalLight{rgb(color, strength), direction.Unit().Muls(float32(-1.0))}
+}
+
+// NewPointLight creates a light at position that shines in all directions
+// The strength is divided by constant + linear*d + quadratic*d^2 at distance d, with the
+// three factors taken from attenuation.
+func NewPointLight(position vector.Vector, color color.Color, strength float32, attenuation vector.Vector) Light {
+	checkVector("NewPointLight", position)
+	checkVector("NewPointLight", attenuation)
+	return pointLight{rgb(color, strength), position, attenuation}
+}
+
+// NewSpotLight creates a point light that only shines within a cone arround direction
+// Within inner degrees from the direction the light is at full strength, it fades out
+// towards outer degrees.
+func NewSpotLight(position vector.Vector, direction vector.Vector, color color.Color, strength float32, attenuation vector.Vector, inner float32, outer float32) Light {
+	checkVector("NewSpotLight", direction)
+	if direction.Abs() == 0.0 {
+		log.Fatalf("Render.NewSpotLight: direction can't be zero")
+	}
+	if inner < 0.0 || outer < inner || outer >= 180.0 {
+		log.Fatalf("Render.NewSpotLight: expects 0 <= inner <= outer < 180, got (i:%f, o:%f)", inner, outer)
+	}
+	return spotLight{
+		pointLight: NewPointLight(position, color, strength, attenuation).(pointLight),
+		direction:  direction.Unit(),
+		cosInner:   math.Cos(float64(inner) * math.Pi / 180.0),
+		cosOuter:   math.Cos(float64(outer) * math.Pi / 180.0),
+	}
+}
+
+// Illuminate implements the Light interface
+func (l ambientLight) Illuminate(position vector.Vector) (vector.Vector, vector.Vector) {
+	return l.intensity, nil
+}
+
+// Illuminate implements the Light interface
+func (l directionalLight) Illuminate(position vector.Vector) (vector.Vector, vector.Vector) {
+	return l.intensity, l.toLight
+}
+
+// Illuminate implements the Light interface
+func (l pointLight) Illuminate(position vector.Vector) (vector.Vector, vector.Vector) {
+	toLight := l.position.Sub(position)
+	d := float32(toLight.Abs())
+	if d == 0.0 {
+		return l.intensity, nil
+	}
+	a := l.attenuation.Get(0).(float32) + l.attenuation.Get(1).(float32)*d + l.attenuation.Get(2).(float32)*d*d
+	if a <= 0.0 {
+		return l.intensity, toLight.Divs(d)
+	}
+	return l.intensity.Divs(a), toLight.Divs(d)
+}
+
+// Illuminate implements the Light interface
+func (l spotLight) Illuminate(position vector.Vector) (vector.Vector, vector.Vector) {
+	intensity, toLight := l.pointLight.Illuminate(position)
+	if toLight == nil {
+		return intensity, nil
+	}
+
+	// Fade out smoothly between the inner and the outer cone
+	cos := -toLight.Mulv(l.direction)
+	switch {
+	case cos >= l.cosInner:
+		return intensity, toLight
+	case cos <= l.cosOuter:
+		return vector.ZeroVector(3, reflect.Float32), toLight
+	}
+	t := (cos - l.cosOuter) / (l.cosInner - l.cosOuter)
+	return intensity.Muls(float32(t * t * (3.0 - 2.0*t))), toLight
+}
+
+// rgb translates a color and strength into an intensity vector
+func rgb(c color.Color, strength float32) vector.Vector {
+	r, g, b, _ := c.RGBA()
+	return vector.NewVector([]float32{
+		float32(r) / 0xffff * strength,
+		float32(g) / 0xffff * strength,
+		float32(b) / 0xffff * strength,
+	})
+}
+
+// checkVector makes sure we get the 3D-Float32 vectors we work with
+func checkVector(caller string, v vector.Vector) {
+	if v.Len() != 3 || v.Kind() != reflect.Float32 {
+		log.Fatalf("Render.%s: expects 3D-Float32 vector, got %dD-%v", caller, v.Len(), v.Kind())
+	}
+}
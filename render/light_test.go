@@ -0,0 +1,62 @@
+package render
+
+import (
+	"image/color"
+	"math"
+	"testing"
+
+	"../model"
+	"../number/vector"
+)
+
+func Test_PointLight(t *testing.T) {
+	light := NewPointLight(vector.NewVector([]float32{0.0, 0.0, 10.0}), color.White, 1.0,
+		vector.NewVector([]float32{1.0, 0.0, 0.01}))
+
+	// Light gets weaker with distance and points back to the light
+	i0, d0 := light.Illuminate(vector.NewVector([]float32{0.0, 0.0, 0.0}))
+	i1, _ := light.Illuminate(vector.NewVector([]float32{0.0, 0.0, -10.0}))
+	if i0.Get(0).(float32) <= i1.Get(0).(float32) {
+		t.Errorf("Expected %v to be brighter than %v", i0, i1)
+	}
+	if !d0.Equal(vector.NewVector([]float32{0.0, 0.0, 1.0})) {
+		t.Errorf("Expected direction (0, 0, 1), got %v", d0)
+	}
+}
+
+func Test_SpotLight(t *testing.T) {
+	light := NewSpotLight(vector.NewVector([]float32{0.0, 0.0, 10.0}), vector.NewVector([]float32{0.0, 0.0, -1.0}),
+		color.White, 1.0, vector.NewVector([]float32{1.0, 0.0, 0.0}), 10.0, 20.0)
+
+	// Full strength in the inner cone, nothing outside the outer cone, in between in between
+	inner, _ := light.Illuminate(vector.NewVector([]float32{0.0, 0.0, 0.0}))
+	edge, _ := light.Illuminate(vector.NewVector([]float32{3.0, 0.0, 0.0}))
+	outer, _ := light.Illuminate(vector.NewVector([]float32{10.0, 0.0, 0.0}))
+	if inner.Get(0).(float32) != 1.0 || outer.Get(0).(float32) != 0.0 {
+		t.Errorf("Expected 1.0 and 0.0, got %v and %v", inner, outer)
+	}
+	if e := edge.Get(0).(float32); e <= 0.0 || e >= 1.0 {
+		t.Errorf("Expected between 0.0 and 1.0, got %v", edge)
+	}
+}
+
+func Test_Shade(t *testing.T) {
+	lighting := NewLighting(FlatShading,
+		NewAmbientLight(color.White, 0.1),
+		NewDirectionalLight(vector.NewVector([]float32{0.0, 0.0, -1.0}), color.White, 0.5))
+	surface := newSurface(model.DefaultMaterial())
+	position := vector.NewVector([]float32{0.0, 0.0, 0.0})
+	sight := vector.NewVector([]float32{1.0, 0.0, 0.0})
+
+	// A surface facing the light gets ambient and diffuse light
+	up := lighting.shade(position, vector.NewVector([]float32{0.0, 0.0, 1.0}), vector.NewVector([]float32{0.0, 0.0, 1.0}).Add(sight).Unit(), surface)
+	if math.Abs(float64(up.Get(0).(float32))-0.6) > 0.05 {
+		t.Errorf("Expected about 0.6, got %v", up)
+	}
+
+	// A surface perpendicular to the light only gets the ambient light
+	side := lighting.shade(position, sight, sight, surface)
+	if math.Abs(float64(side.Get(0).(float32))-0.1) > 1e-6 {
+		t.Errorf("Expected 0.1, got %v", side)
+	}
+}
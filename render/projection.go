@@ -0,0 +1,140 @@
+package render
+
+import (
+	"log"
+	"math"
+
+	"../number/matrix"
+	"../number/vector"
+)
+
+// Projection selects how the camera flattens the model onto the view
+type Projection int
+
+const (
+	// Perspective makes things smaller the further they are away from the camera
+	Perspective Projection = iota
+	// Orthographic keeps parallel lines parallel, the view volume is a box
+	Orthographic
+)
+
+// Preset is a standard parallel projection as used in technical drawings
+type Preset int
+
+const (
+	// FrontView looks along the positive y-axis with z up
+	FrontView Preset = iota
+	// TopView looks down the negative z-axis with y up
+	TopView
+	// SideView looks along the negative x-axis with z up
+	SideView
+	// IsometricView shows all three axis equally foreshortened
+	IsometricView
+	// DimetricView shows x and y equally foreshortened, viewed from 30deg above (2:1 pixel-art style)
+	DimetricView
+	// CavalierView is the front view with the y-axis receding at 45deg in full length
+	CavalierView
+	// CabinetView is the front view with the y-axis receding at 45deg in half length
+	CabinetView
+)
+
+// SetProjection switches between perspective and orthographic projection
+func (c *Camera) SetProjection(projection Projection) {
+	if projection != Perspective && projection != Orthographic {
+		log.Fatalf("Camera.SetProjection: unknown projection %d", projection)
+	}
+	c.projection = projection
+}
+
+// SetViewHeight sets the height of the orthographic view volume, the width follows from the aspect ratio
+// A height of 0 frames the same area at the lookat point as the perspective field of view does
+func (c *Camera) SetViewHeight(height float32) {
+	if height < 0.0 {
+		log.Fatalf("Camera.SetViewHeight: expects a positive height, got %f", height)
+	}
+	c.viewHeight = height
+}
+
+// SetOblique shears the orthographic projection so the line of sight shows up as a receding axis
+// at angle degrees, foreshortened by factor. A factor of 0 switches the oblique projection off.
+func (c *Camera) SetOblique(factor float32, angle float32) {
+	if factor < 0.0 {
+		log.Fatalf("Camera.SetOblique: expects a positive factor, got %f", factor)
+	}
+	c.oblique = factor
+	c.obliqueAngle = angle
+}
+
+// UsePreset moves the camera arround the lookat point, keeping its distance, and
+// sets up the orthographic projection for one of the standard parallel projections
+func (c *Camera) UsePreset(preset Preset) {
+	distance := float32(c.position.Sub(c.lookat).Abs())
+	if distance == 0.0 {
+		log.Fatalf("Camera.UsePreset: Camera position is the same as camera lookat")
+	}
+
+	zUp := vector.NewVector([]float32{0.0, 0.0, 1.0})
+	elevation := 0.0
+	direction := vector.NewVector([]float32{0.0, -1.0, 0.0})
+	up := zUp
+	oblique := float32(0.0)
+
+	switch preset {
+	case FrontView:
+	case TopView:
+		direction = zUp
+		up = vector.NewVector([]float32{0.0, 1.0, 0.0})
+	case SideView:
+		direction = vector.NewVector([]float32{1.0, 0.0, 0.0})
+	case IsometricView:
+		elevation = math.Asin(1.0 / math.Sqrt(3.0))
+	case DimetricView:
+		elevation = math.Pi / 6.0
+	case CavalierView:
+		oblique = 1.0
+	case CabinetView:
+		oblique = 0.5
+	default:
+		log.Fatalf("Camera.UsePreset: unknown preset %d", preset)
+	}
+
+	// Axonometric views look from the front-right corner, elevated above the xy-plane
+	if elevation != 0.0 {
+		direction = vector.NewVector([]float32{
+			float32(math.Cos(elevation) * math.Sqrt(0.5)),
+			float32(-math.Cos(elevation) * math.Sqrt(0.5)),
+			float32(math.Sin(elevation)),
+		})
+	}
+
+	c.position = c.lookat.Add(direction.Muls(distance))
+	c.up = up
+	c.projection = Orthographic
+	c.SetOblique(oblique, 45.0)
+}
+
+// orthographicMatrix provides the parallel projection into clip space, including the oblique shear
+func (c *Camera) orthographicMatrix() matrix.Matrix {
+	height := c.viewHeight
+	distance := float32(c.position.Sub(c.lookat).Abs())
+	if height == 0.0 {
+		height = 2.0 * distance * float32(math.Tan(float64(c.fov)*math.Pi/360.0))
+	}
+	width := height * c.aspect
+	ortho := matrix.Orthographic(-width/2.0, width/2.0, -height/2.0, height/2.0, c.near, c.far)
+	if c.oblique == 0.0 {
+		return ortho
+	}
+
+	// Shift x and y by the depth behind the lookat plane, which is -z-distance in camera coordinates
+	angle := float64(c.obliqueAngle) * math.Pi / 180.0
+	dx := c.oblique * float32(math.Cos(angle))
+	dy := c.oblique * float32(math.Sin(angle))
+	shear := matrix.NewMatrix([][]float32{
+		{1.0, 0.0, -dx, -dx * distance},
+		{0.0, 1.0, -dy, -dy * distance},
+		{0.0, 0.0, 1.0, 0.0},
+		{0.0, 0.0, 0.0, 1.0},
+	})
+	return ortho.Mulm(shear)
+}
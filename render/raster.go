@@ -0,0 +1,91 @@
+package render
+
+import (
+	"image/color"
+	"math"
+
+	"../number/vector"
+)
+
+// fillTriangle fills a triangle given in screen coordinates (x, y in pixels, depth, 1/w, attributes...)
+// Every pixel whose center lies inside the triangle is tested against the depth buffer
+// of the canvas, so the triangles can be drawn in any order. The shader gets the perspective
+// correct interpolation of the attributes for every visible pixel and provides its color.
+func fillTriangle(a vector.Vector, b vector.Vector, c vector.Vector, canvas *Canvas, shader func(attributes []float32) color.Color) {
+	ax, ay, az := float64(a.Get(0).(float32)), float64(a.Get(1).(float32)), float64(a.Get(2).(float32))
+	bx, by, bz := float64(b.Get(0).(float32)), float64(b.Get(1).(float32)), float64(b.Get(2).(float32))
+	cx, cy, cz := float64(c.Get(0).(float32)), float64(c.Get(1).(float32)), float64(c.Get(2).(float32))
+
+	// Twice the signed area, the sign depends on the winding
+	area := edge(ax, ay, bx, by, cx, cy)
+	if area == 0.0 {
+		return
+	}
+
+	// Pull the attributes out of the vectors once, not for every pixel
+	aw, bw, cw := float64(a.Get(3).(float32)), float64(b.Get(3).(float32)), float64(c.Get(3).(float32))
+	count := a.Len() - 4
+	at, bt, ct := make([]float64, count), make([]float64, count), make([]float64, count)
+	for i := 0; i < count; i++ {
+		at[i] = float64(a.Get(i + 4).(float32))
+		bt[i] = float64(b.Get(i + 4).(float32))
+		ct[i] = float64(c.Get(i + 4).(float32))
+	}
+	attributes := make([]float32, count)
+
+	// Turn the triangle so the area is positive, the fill rule depends on the direction of the edges
+	if area < 0.0 {
+		bx, by, bz, bw, bt, cx, cy, cz, cw, ct = cx, cy, cz, cw, ct, bx, by, bz, bw, bt
+		area = -area
+	}
+
+	// Only visit the pixels in the bounding box that are on the canvas
+	minX := int(math.Max(math.Floor(math.Min(ax, math.Min(bx, cx))), 0.0))
+	maxX := int(math.Min(math.Ceil(math.Max(ax, math.Max(bx, cx))), float64(canvas.Width()-1)))
+	minY := int(math.Max(math.Floor(math.Min(ay, math.Min(by, cy))), 0.0))
+	maxY := int(math.Min(math.Ceil(math.Max(ay, math.Max(by, cy))), float64(canvas.Height()-1)))
+
+	for y := minY; y <= maxY; y++ {
+		py := float64(y) + 0.5
+		for x := minX; x <= maxX; x++ {
+			px := float64(x) + 0.5
+
+			// Barycentric weights, all positive when inside
+			e0, e1, e2 := edge(bx, by, cx, cy, px, py), edge(cx, cy, ax, ay, px, py), edge(ax, ay, bx, by, px, py)
+			if !owns(e0, cx-bx, cy-by) || !owns(e1, ax-cx, ay-cy) || !owns(e2, bx-ax, by-ay) {
+				continue
+			}
+			w0, w1, w2 := e0/area, e1/area, e2/area
+
+			// Depth after the perspective divide is linear in screen space
+			depth := float32(w0*az + w1*bz + w2*cz)
+			if depth >= canvas.Depth(x, y) {
+				continue
+			}
+
+			// Attributes are linear in world space, so we have to undo the perspective
+			q0, q1, q2 := w0*aw, w1*bw, w2*cw
+			sum := q0 + q1 + q2
+			for i := range attributes {
+				attributes[i] = float32((q0*at[i] + q1*bt[i] + q2*ct[i]) / sum)
+			}
+
+			canvas.Plot(x, y, depth, shader(attributes))
+		}
+	}
+}
+
+// owns tells if a pixel center with edge function e is on the inside of an edge running (dx, dy)
+// Centers exactly on the edge belong to it only on the top and left edges of a triangle, so a pixel
+// on an edge shared by two triangles is filled once: the other triangle runs the edge the other way.
+func owns(e float64, dx float64, dy float64) bool {
+	if e != 0.0 {
+		return e > 0.0
+	}
+	return dy < 0.0 || (dy == 0.0 && dx > 0.0)
+}
+
+// edge is the edge function: twice the signed area of the triangle (a, b, p)
+func edge(ax float64, ay float64, bx float64, by float64, px float64, py float64) float64 {
+	return (bx-ax)*(py-ay) - (by-ay)*(px-ax)
+}
@@ -0,0 +1,124 @@
+package render
+
+import (
+	"image/color"
+	"testing"
+
+	"../number/vector"
+)
+
+func Test_FillTriangle(t *testing.T) {
+	canvas := NewCanvas(20, 20)
+	near := vector.NewVector([]float32{0.0, 0.0, 0.2})
+	far := vector.NewVector([]float32{0.0, 0.0, 0.8})
+	red := color.RGBA{0xff, 0x00, 0x00, 0xff}
+	blue := color.RGBA{0x00, 0x00, 0xff, 0xff}
+
+	// A near triangle drawn before a far one stays visible
+	fillTriangle(
+		vector.NewVector([]float32{0.0, 0.0, 0.2, 1.0}),
+		vector.NewVector([]float32{20.0, 0.0, 0.2, 1.0}),
+		vector.NewVector([]float32{0.0, 20.0, 0.2, 1.0}),
+		canvas, func([]float32) color.Color { return red })
+	fillTriangle(
+		vector.NewVector([]float32{0.0, 0.0, 0.8, 1.0}),
+		vector.NewVector([]float32{0.0, 20.0, 0.8, 1.0}),
+		vector.NewVector([]float32{20.0, 20.0, 0.8, 1.0}),
+		canvas, func([]float32) color.Color { return blue })
+
+	if canvas.Depth(2, 10) != near.Get(2).(float32) || canvas.Pixels()[(10*20+2)*4] != 0xff {
+		t.Errorf("Expected the near triangle at (2, 10), got depth %f", canvas.Depth(2, 10))
+	}
+	if canvas.Depth(15, 18) != far.Get(2).(float32) || canvas.Pixels()[(18*20+15)*4+2] != 0xff {
+		t.Errorf("Expected the far triangle at (15, 18), got depth %f", canvas.Depth(15, 18))
+	}
+
+	// Pixels outside of both triangles are untouched
+	canvas.Clear()
+	fillTriangle(
+		vector.NewVector([]float32{0.0, 0.0, 0.5, 1.0}),
+		vector.NewVector([]float32{10.0, 0.0, 0.5, 1.0}),
+		vector.NewVector([]float32{0.0, 10.0, 0.5, 1.0}),
+		canvas, func([]float32) color.Color { return red })
+	if canvas.Pixels()[(15*20+15)*4] != 0x00 {
+		t.Errorf("Expected (15, 15) to stay clear")
+	}
+}
+
+func Test_FillRule(t *testing.T) {
+	// A square of two triangles, the diagonal and the sides run through pixel centers
+	corners := [][]float32{{0.5, 0.5}, {6.5, 0.5}, {6.5, 6.5}, {0.5, 6.5}}
+	fill := func(canvas *Canvas, counts []int, triangle [3]int) {
+		vertices := make([]vector.Vector, 3)
+		for i, c := range triangle {
+			// The position is passed on as attributes, to find the pixel in the shader
+			vertices[i] = vector.NewVector([]float32{corners[c][0], corners[c][1], 0.5, 1.0, corners[c][0], corners[c][1]})
+		}
+		fillTriangle(vertices[0], vertices[1], vertices[2], canvas, func(attributes []float32) color.Color {
+			counts[int(attributes[1])*8+int(attributes[0])]++
+			return color.Transparent
+		})
+	}
+
+	counts := make([]int, 64)
+	fill(NewCanvas(8, 8), counts, [3]int{0, 1, 2})
+	fill(NewCanvas(8, 8), counts, [3]int{0, 2, 3})
+	for i, count := range counts {
+		if count > 1 {
+			t.Errorf("Expected (%d, %d) to be filled once, got %d times", i%8, i/8, count)
+		}
+	}
+	for i := 1; i < 6; i++ {
+		if counts[i*8+i] != 1 {
+			t.Errorf("Expected (%d, %d) on the diagonal to be filled", i, i)
+		}
+	}
+
+	// The winding doesn't change which pixels a triangle gets
+	clockwise := make([]int, 64)
+	fill(NewCanvas(8, 8), clockwise, [3]int{2, 1, 0})
+	fill(NewCanvas(8, 8), clockwise, [3]int{3, 2, 0})
+	for i := range counts {
+		if counts[i] != clockwise[i] {
+			t.Errorf("Expected (%d, %d) to be filled %d times, got %d", i%8, i/8, counts[i], clockwise[i])
+		}
+	}
+}
+
+func Test_FillTriangleAttributes(t *testing.T) {
+	canvas := NewCanvas(20, 20)
+
+	// A single attribute running from 0 to 1 accross the triangle
+	var seen []float32
+	fillTriangle(
+		vector.NewVector([]float32{0.0, 0.0, 0.5, 1.0, 0.0}),
+		vector.NewVector([]float32{20.0, 0.0, 0.5, 1.0, 1.0}),
+		vector.NewVector([]float32{0.0, 20.0, 0.5, 1.0, 0.0}),
+		canvas, func(attributes []float32) color.Color {
+			seen = append(seen, attributes[0])
+			return color.White
+		})
+
+	if len(seen) == 0 {
+		t.Fatalf("Expected the shader to be called")
+	}
+	for _, a := range seen {
+		if a < 0.0 || a > 1.0 {
+			t.Errorf("Expected attribute in [0..1], got %f", a)
+		}
+	}
+}
+
+func Test_PlotTranslucent(t *testing.T) {
+	canvas := NewCanvas(2, 2)
+	canvas.Plot(0, 0, 0.5, color.RGBA{0x00, 0x00, 0xff, 0xff})
+
+	// Half transparent red in front of blue mixes, but doesn't hide what's behind it
+	canvas.Plot(0, 0, 0.2, color.NRGBA{0xff, 0x00, 0x00, 0x80})
+	if r, b := canvas.Pixels()[0], canvas.Pixels()[2]; r < 0x70 || r > 0x90 || b < 0x70 || b > 0x90 {
+		t.Errorf("Expected a purple mix, got (%d, %d)", r, b)
+	}
+	if canvas.Depth(0, 0) != 0.5 {
+		t.Errorf("Expected depth 0.5, got %f", canvas.Depth(0, 0))
+	}
+}
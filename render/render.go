@@ -1,47 +1,94 @@
 package render
 
 import (
-	"fmt"
+	"image/color"
 	"log"
+	"math"
 
 	"../model"
+	"../number/matrix"
 	"../number/vector"
 )
 
-// Color ...
-// Replace with standard GOLANG Color please
-// Going to be part of the Model package, and will include many more properties
-type Color struct {
-	r, g, b byte
-}
-
 // Canvas defines a drawing area compatible with SDL 2.0
+// It keeps a depth buffer next to the pixels so nearer surfaces can hide farther ones
 type Canvas struct {
 	width  int
 	height int
 	pixels []byte
+	depth  []float32
 }
 
 // NewCanvas creates a Canvas
 func NewCanvas(width int, height int) *Canvas {
-	return &Canvas{width, height, make([]byte, width*height*4)}
+	c := &Canvas{width, height, make([]byte, width*height*4), make([]float32, width*height)}
+	c.Clear()
+	return c
 }
 
-// Clear ...
+// Clear resets the pixels to black and the depth buffer to infinitely far away
 func (c *Canvas) Clear() {
 	for i := range c.pixels {
 		c.pixels[i] = 0
 	}
+	for i := range c.depth {
+		c.depth[i] = math.MaxFloat32
+	}
 }
 
 // Set ...
-func (c *Canvas) Set(x int, y int, color Color) {
+func (c *Canvas) Set(x int, y int, color color.Color) {
+	if x < 0 || x >= c.width || y < 0 || y >= c.height {
+		return
+	}
 	index := (y*c.width + x) * 4
-	if index < len(c.pixels)-4 && index >= 0 {
-		c.pixels[index] = color.r
-		c.pixels[index+1] = color.g
-		c.pixels[index+2] = color.b
+	r, g, b, _ := color.RGBA()
+	c.pixels[index] = byte(r >> 8)
+	c.pixels[index+1] = byte(g >> 8)
+	c.pixels[index+2] = byte(b >> 8)
+}
+
+// Plot sets a pixel only if it is nearer than what has been drawn on that spot before
+// A translucent color is blended with what is already there and doesn't hide anything
+// drawn later. It reports if the pixel was set.
+func (c *Canvas) Plot(x int, y int, depth float32, color color.Color) bool {
+	if x < 0 || x >= c.width || y < 0 || y >= c.height {
+		return false
+	}
+	index := y*c.width + x
+	if depth >= c.depth[index] {
+		return false
+	}
+
+	r, g, b, a := color.RGBA()
+	switch {
+	case a == 0:
+		return false
+	case a == 0xffff:
+		c.depth[index] = depth
+		c.pixels[index*4] = byte(r >> 8)
+		c.pixels[index*4+1] = byte(g >> 8)
+		c.pixels[index*4+2] = byte(b >> 8)
+	default:
+		// The color is alpha-premultiplied already
+		c.pixels[index*4] = blend(r, c.pixels[index*4], a)
+		c.pixels[index*4+1] = blend(g, c.pixels[index*4+1], a)
+		c.pixels[index*4+2] = blend(b, c.pixels[index*4+2], a)
+	}
+	return true
+}
+
+// blend puts an alpha-premultiplied channel over a pixel channel
+func blend(src uint32, dst byte, alpha uint32) byte {
+	return byte((src + uint32(dst)*0x101*(0xffff-alpha)/0xffff) >> 8)
+}
+
+// Depth retrieves the depth buffer value of a pixel
+func (c *Canvas) Depth(x int, y int) float32 {
+	if x < 0 || x >= c.width || y < 0 || y >= c.height {
+		return math.MaxFloat32
 	}
+	return c.depth[y*c.width+x]
 }
 
 // Width ...
@@ -59,109 +106,181 @@ func (c *Canvas) Pixels() []byte {
 	return c.pixels
 }
 
-// Camera ...
-type Camera struct {
-	position vector.Vector
-	lookat   vector.Vector // TODO: should be normalized
-}
-
-// NewCamera creates a camera
-func NewCamera(postion vector.Vector, lookat vector.Vector) *Camera {
-	return &Camera{postion, lookat}
+// toScreen translates a point in clip space into pixel coordinates on the canvas
+// The result holds (x, y, depth, 1/w), followed by any attributes carried by the point
+func toScreen(clip vector.Vector, canvas *Canvas) vector.Vector {
+	p := normalize(clip)
+	screen := []float32{
+		p.Get(0).(float32) * float32(canvas.Width()),
+		(1.0 - p.Get(1).(float32)) * float32(canvas.Height()),
+		p.Get(2).(float32),
+		1.0 / clip.Get(3).(float32),
+	}
+	for i := 4; i < clip.Len(); i++ {
+		screen = append(screen, clip.Get(i).(float32))
+	}
+	return vector.NewVector(screen)
 }
 
-// Project translates a point into the view of the camera
-// The new vector represents values between ([0..1], [0..1], distance)
-func (c *Camera) Project(point vector.Vector) vector.Vector {
-	// Translate the Camera Direction relative to the camera position
-	// giving a vector perpendicular the the eventual camera plain
-	if c.position.Equal(c.lookat) {
-		log.Fatalf("Render.Project: Camera position is the same as camera lookat")
+// drawLine draws the part of a line, given in clip space, that is in view
+func drawLine(from vector.Vector, to vector.Vector, canvas *Canvas, color color.Color) {
+	from, to, visible := clipLine(from, to)
+	if !visible {
+		return
 	}
-	cNormal := c.lookat.Sub(c.position).Unit()
-	fmt.Printf("Camera normal: |%v| = %f\n", cNormal, cNormal.Abs())
 
-	// Define the camera plane by creating 3 vectors:
-	// 1 a Vector that defines the unit vector in the y-direction on this
-	//   plane by rotating the normal 90deg
-
-	// 1 a Vector that definines the position of the plane as 1 unit behind
-	//   the current camera postion.
-	// cSupport := c.position.Sub(cNormal)
-	// Normal rotated arround the y-axis to cancel out x
-
-	// 2 a Vector on the xy-plane that defines the x direction coordinate
-	//   for any projected point
-	xProjection := vector.NewVector([]float32{cNormal.Get(0).(float32), cNormal.Get(1).(float32), 0.0}).Unit()
-	xCoordinate := -xProjection.Get(1).(float32) * xProjection.Get(1).(float32)
-	yCoordinate := xProjection.Get(1).(float32) * xProjection.Get(0).(float32)
-	xUnit := vector.NewVector([]float32{xCoordinate, yCoordinate, 0.0}).Unit()
-	fmt.Printf("Unit vector for X: |%v| = %f\n", xUnit, xUnit.Abs())
+	// create the direction of travel in pixels and run allong the line
+	from = toScreen(from, canvas)
+	to = toScreen(to, canvas)
+	dv := to.Sub(from).Unit()
+	for p := from; p.Sub(to).Abs() >= 1.0; p = p.Add(dv) {
+		x := int(p.Get(0).(float32))
+		y := int(p.Get(1).(float32))
+		if x >= 0 && x < canvas.Width() && y >= 0 && y < canvas.Height() {
+			canvas.Set(x, y, color)
+		}
+	}
+}
 
-	// 3 the Normal of the plane defined by the origin, the camera normal and the 
-	//   xUnit vector defines the yUnit vector for the view plane
-	
-	// Translate the line of sight from the point to a vector representation
-	// take care of the edge case where the point happens to be on the camera
-	if point.Equal(c.position) {
-		return vector.ZeroVector(point.Len(), point.Kind())
+// Draw a single triangular Mesh as a solid surface
+// Vertex normals make the surface look curved, vertex colors tint the diffuse color and
+// texture coordinates look up the diffuse color in the texture of the material.
+func drawMesh(mesh model.Mesh, camera *Camera, transform matrix.Matrix, lighting *Lighting, canvas *Canvas) {
+	vertices := []vector.Vector{mesh.GetVertex(0), mesh.GetVertex(1), mesh.GetVertex(2)}
+	normal := mesh.Normal()
+	if normal.Abs() == 0.0 {
+		return
 	}
-	lnormal := c.position.Sub(point).Unit()
+	material := mesh.GetMaterial()
+	if material == nil {
+		material = model.DefaultMaterial()
+	}
+	surface := newSurface(material)
 
-	// Project the viewline onto the view plane
-	t := float32((cNormal.Mulv(c.position) - cNormal.Mulv(point)) / cNormal.Mulv(lnormal))
-	r := point.Add(lnormal.Muls(t))
+	// The attributes of the corners, falling back to the flat surface
+	normals := make([]vector.Vector, 3)
+	tints := make([]vector.Vector, 3)
+	for i := range vertices {
+		if normals[i] = mesh.GetNormal(i); normals[i] == nil {
+			normals[i] = normal
+		}
+		tints[i] = vector.NewVector([]float32{1.0, 1.0, 1.0})
+		if c := mesh.GetColor(i); c != nil {
+			tints[i] = rgb(c, 1.0)
+		}
+	}
+	texture := material.GetTexture()
+	if mesh.GetUV(0) == nil {
+		texture = nil
+	}
 
-	// Translate the intersection to an x,y & depth coordinate
-	return r
-}
+	// The texture replaces the diffuse color, flat and Gouraud shading apply it after lighting
+	diffuse := surface.diffuse
+	if texture != nil {
+		diffuse = vector.NewVector([]float32{1.0, 1.0, 1.0})
+	}
 
-// cringeworthy version
-func drawLine(from vector.Vector, to vector.Vector, canvas *Canvas, color Color) {
-	// create the direction of travel and run allong the line
-	dv := to.Sub(from).Unit()
-	for p := from; p.Sub(to).Abs() >= 1.0; p = p.Add(dv) {
-		x := int(p.Get(0).(float32) + float32(canvas.Width())/2.0)
-		y := int(-p.Get(1).(float32) + float32(canvas.Height())/2.0)
-		if x >= 0 && x < canvas.Width() && y >= 0 && y < canvas.Height() {
-			canvas.Set(x, y, color)
+	// Decide what to carry from the corners to the pixels, the texture coordinates go last
+	attributes := make([][]float32, 3)
+	var shader func(attributes []float32) color.Color
+	switch lighting.shading {
+	case FlatShading:
+		center := vertices[0].Add(vertices[1]).Add(vertices[2]).Divs(float32(3.0))
+		tinted := surface
+		tinted.diffuse = modulate(diffuse, tints[0].Add(tints[1]).Add(tints[2]).Divs(float32(3.0)))
+		lit, specular := lighting.light(center, normal, camera.sight(center), tinted)
+		shader = func(attributes []float32) color.Color {
+			return toColor(textured(lit, specular, texture, attributes), surface.opacity)
+		}
+	case GouraudShading:
+		for i, v := range vertices {
+			tinted := surface
+			tinted.diffuse = modulate(diffuse, tints[i])
+			lit, specular := lighting.light(v, normals[i], camera.sight(v), tinted)
+			attributes[i] = append(components(lit), components(specular)...)
+		}
+		shader = func(attributes []float32) color.Color {
+			return toColor(textured(vector.NewVector(attributes[0:3]), vector.NewVector(attributes[3:6]), texture, attributes), surface.opacity)
 		}
+	case PhongShading:
+		for i, v := range vertices {
+			attributes[i] = append(append(components(v), components(normals[i])...), components(tints[i])...)
+		}
+		shader = func(attributes []float32) color.Color {
+			position := vector.NewVector(attributes[0:3])
+			tinted := surface
+			if texture != nil {
+				tinted.diffuse = sample(texture, attributes[len(attributes)-2], attributes[len(attributes)-1])
+			}
+			tinted.diffuse = modulate(tinted.diffuse, vector.NewVector(attributes[6:9]))
+			return toColor(lighting.shade(position, vector.NewVector(attributes[3:6]).Unit(), camera.sight(position), tinted), surface.opacity)
+		}
+	}
+	if texture != nil {
+		for i := range attributes {
+			attributes[i] = append(attributes[i], components(mesh.GetUV(i))...)
+		}
+	}
+
+	// Clip against the view volume, what is left is a convex polygon we draw as a fan
+	polygon := make([]vector.Vector, 3)
+	for i, v := range vertices {
+		polygon[i] = vector.NewVector(append(components(transform.Mulv(vector.Homogeneous(v))), attributes[i]...))
+	}
+	polygon = clipPolygon(polygon)
+	if len(polygon) < 3 {
+		return
+	}
+	screen := make([]vector.Vector, len(polygon))
+	for i, p := range polygon {
+		screen[i] = toScreen(p, canvas)
+	}
+	for i := 1; i < len(screen)-1; i++ {
+		fillTriangle(screen[0], screen[i], screen[i+1], canvas, shader)
 	}
 }
 
-// Draw a line diagram of a single triangular Mesh
-func drawMesh(mesh model.Mesh, camera *Camera, canvas *Canvas) {
-	drawLine(camera.Project(mesh.GetVertex(0)), camera.Project(mesh.GetVertex(1)), canvas, Color{0xff, 0xff, 0xff})
-	drawLine(camera.Project(mesh.GetVertex(1)), camera.Project(mesh.GetVertex(2)), canvas, Color{0xff, 0xff, 0xff})
-	drawLine(camera.Project(mesh.GetVertex(2)), camera.Project(mesh.GetVertex(0)), canvas, Color{0xff, 0xff, 0xff})
+// components pulls the values out of a Float32 vector
+func components(v vector.Vector) []float32 {
+	result := make([]float32, v.Len())
+	for i := range result {
+		result[i] = v.Get(i).(float32)
+	}
+	return result
 }
 
 // For testing only
-func drawGrid(camera *Camera, canvas *Canvas) {
-	oo := vector.NewVector([]float32{0.0, 0.0, 0.0})
-	xp := vector.NewVector([]float32{500.0, 0.0, 0.0})
-	xn := vector.NewVector([]float32{-500.0, 0.0, 0.0})
-	yp := vector.NewVector([]float32{0.0, 500.0, 0.0})
-	yn := vector.NewVector([]float32{0.0, -500.0, 0.0})
-	zp := vector.NewVector([]float32{0.0, 0.0, 500.0})
-	zn := vector.NewVector([]float32{0.0, 0.0, -500.0})
-
-	drawLine(camera.Project(oo), camera.Project(xp), canvas, Color{0xff, 0x00, 0x00})
-	drawLine(camera.Project(oo), camera.Project(xn), canvas, Color{0x80, 0x80, 0x80})
-	drawLine(camera.Project(oo), camera.Project(yp), canvas, Color{0x00, 0xff, 0x00})
-	drawLine(camera.Project(oo), camera.Project(yn), canvas, Color{0x80, 0x80, 0x80})
-	drawLine(camera.Project(oo), camera.Project(zp), canvas, Color{0x00, 0x00, 0xff})
-	drawLine(camera.Project(oo), camera.Project(zn), canvas, Color{0x80, 0x80, 0x80})
+func drawGrid(transform matrix.Matrix, canvas *Canvas) {
+	oo := transform.Mulv(vector.NewVector([]float32{0.0, 0.0, 0.0, 1.0}))
+	xp := transform.Mulv(vector.NewVector([]float32{500.0, 0.0, 0.0, 1.0}))
+	xn := transform.Mulv(vector.NewVector([]float32{-500.0, 0.0, 0.0, 1.0}))
+	yp := transform.Mulv(vector.NewVector([]float32{0.0, 500.0, 0.0, 1.0}))
+	yn := transform.Mulv(vector.NewVector([]float32{0.0, -500.0, 0.0, 1.0}))
+	zp := transform.Mulv(vector.NewVector([]float32{0.0, 0.0, 500.0, 1.0}))
+	zn := transform.Mulv(vector.NewVector([]float32{0.0, 0.0, -500.0, 1.0}))
+
+	drawLine(oo, xp, canvas, color.RGBA{0xff, 0x00, 0x00, 0xff})
+	drawLine(oo, xn, canvas, color.RGBA{0x80, 0x80, 0x80, 0xff})
+	drawLine(oo, yp, canvas, color.RGBA{0x00, 0xff, 0x00, 0xff})
+	drawLine(oo, yn, canvas, color.RGBA{0x80, 0x80, 0x80, 0xff})
+	drawLine(oo, zp, canvas, color.RGBA{0x00, 0x00, 0xff, 0xff})
+	drawLine(oo, zn, canvas, color.RGBA{0x80, 0x80, 0x80, 0xff})
 }
 
 // Draw puts the model on the canvas given current Camera and Lighting settings
-func Draw(meshes []model.Mesh, camera *Camera, canvas *Canvas) {
+func Draw(meshes []model.Mesh, camera *Camera, lighting *Lighting, canvas *Canvas) {
+	if lighting == nil {
+		log.Fatalf("Render.Draw: lighting can't be nil")
+	}
 
 	canvas.Clear()
 
+	// One transformation from world to clip space for everything
+	transform := camera.Matrix()
+
 	// Run trough the meshes
-	drawGrid(camera, canvas)
+	drawGrid(transform, canvas)
 	for _, mesh := range meshes {
-		drawMesh(mesh, camera, canvas)
+		drawMesh(mesh, camera, transform, lighting, canvas)
 	}
 }
@@ -1,18 +1,223 @@
 package render
 
 import (
-	"fmt"
+	"errors"
+	"image"
+	"image/color"
+	"math"
 	"testing"
 
+	"../model"
 	"../number/vector"
 )
 
 func Test_Project(t *testing.T) {
-	pos := vector.NewVector([]float32{1.0, 1.0, 100.0})
+	pos := vector.NewVector([]float32{0.0, 0.0, 100.0})
 	dir := vector.NewVector([]float32{0.0, 0.0, 0.0})
 	camera := NewCamera(pos, dir)
+	camera.SetUp(vector.NewVector([]float32{0.0, 1.0, 0.0}))
 
-	t0 := vector.NewVector([]float32{0.0, 0.0, 0.0})
-	r0 := camera.Project(t0)
-	fmt.Printf("Camera Projection: %v --> %v\n", t0, r0)
+	// The point we look at ends up in the middle of the view
+	r0 := camera.Project(vector.NewVector([]float32{0.0, 0.0, 0.0}))
+	if math.Abs(float64(r0.Get(0).(float32))-0.5) > 1e-6 || math.Abs(float64(r0.Get(1).(float32))-0.5) > 1e-6 {
+		t.Errorf("Expected (0.5, 0.5, d), got %v", r0)
+	}
+
+	// Positive x is on the right, positive y (up) is on top
+	r1 := camera.Project(vector.NewVector([]float32{10.0, 10.0, 0.0}))
+	if r1.Get(0).(float32) <= 0.5 || r1.Get(1).(float32) <= 0.5 {
+		t.Errorf("Expected top-right of the view, got %v", r1)
+	}
+
+	// Depth runs from 0 on the near plane to 1 on the far plane
+	rn := camera.Project(vector.NewVector([]float32{0.0, 0.0, 99.0}))
+	if math.Abs(float64(rn.Get(2).(float32))) > 1e-4 {
+		t.Errorf("Expected depth 0 on the near plane, got %v", rn)
+	}
+	rf := camera.Project(vector.NewVector([]float32{0.0, 0.0, -900.0}))
+	if math.Abs(float64(rf.Get(2).(float32))-1.0) > 1e-4 {
+		t.Errorf("Expected depth 1 on the far plane, got %v", rf)
+	}
+
+	// Nearer points have a smaller depth
+	if r0.Get(2).(float32) >= rf.Get(2).(float32) || r0.Get(2).(float32) <= rn.Get(2).(float32) {
+		t.Errorf("Expected %v between %v and %v", r0, rn, rf)
+	}
+}
+
+func Test_TryProject(t *testing.T) {
+	camera := NewCamera(vector.NewVector([]float32{0.0, -100.0, 0.0}), vector.NewVector([]float32{0.0, 0.0, 0.0}))
+	tests := []struct {
+		point    vector.Vector
+		expected error
+	}{
+		{vector.NewVector([]float32{0.0, 0.0}), ErrDimension},
+		{vector.NewVector([]float64{0.0, 0.0, 0.0}), ErrDimension},
+		{vector.NewVector([]float32{0.0, -200.0, 0.0}), ErrBehindCamera},
+		{vector.NewVector([]float32{10.0, -100.0, 0.0}), ErrBehindCamera},
+	}
+	for i, test := range tests {
+		p, err := camera.TryProject(test.point)
+		var e *Error
+		if p != nil || !errors.Is(err, test.expected) || !errors.As(err, &e) || e.Op != "Camera.Project" {
+			t.Errorf("Test %d: expected %v, got %v %v", i, test.expected, p, err)
+		}
+	}
+	if p, err := camera.TryProject(vector.NewVector([]float32{0.0, 0.0, 0.0})); err != nil || !p.Equal(camera.Project(vector.NewVector([]float32{0.0, 0.0, 0.0}))) {
+		t.Errorf("Expected the same as Project, got %v %v", p, err)
+	}
+
+	camera.SetLookat(vector.NewVector([]float32{0.0, -100.0, 0.0}))
+	if _, err := camera.TryProject(vector.NewVector([]float32{0.0, 0.0, 0.0})); !errors.Is(err, ErrCameraOnLookat) {
+		t.Errorf("Expected %v, got %v", ErrCameraOnLookat, err)
+	}
+}
+
+func Test_Unproject(t *testing.T) {
+	camera := NewCamera(vector.NewVector([]float32{120.0, -80.0, 45.0}), vector.NewVector([]float32{10.0, 20.0, -5.0}))
+	for _, projection := range []Projection{Perspective, Orthographic} {
+		camera.SetProjection(projection)
+		point := vector.NewVector([]float32{15.0, 10.0, 5.0})
+		back, err := camera.Unproject(camera.Project(point))
+		if err != nil || back.Sub(point).Abs() > 1e-2 {
+			t.Errorf("Projection %d: expected %v, got %v %v", projection, point, back, err)
+		}
+	}
+}
+
+func Test_ProjectAnyPlacement(t *testing.T) {
+	// A camera off-axis should still see its lookat point in the center
+	pos := vector.NewVector([]float32{120.0, -80.0, 45.0})
+	dir := vector.NewVector([]float32{10.0, 20.0, -5.0})
+	camera := NewCamera(pos, dir)
+
+	r := camera.Project(dir)
+	if math.Abs(float64(r.Get(0).(float32))-0.5) > 1e-4 || math.Abs(float64(r.Get(1).(float32))-0.5) > 1e-4 {
+		t.Errorf("Expected (0.5, 0.5, d), got %v", r)
+	}
+}
+
+func Test_ProjectOrthographic(t *testing.T) {
+	camera := NewCamera(vector.NewVector([]float32{0.0, -100.0, 0.0}), vector.NewVector([]float32{0.0, 0.0, 0.0}))
+	camera.SetProjection(Orthographic)
+	camera.SetViewHeight(100.0)
+
+	// Depth doesn't change the position in the view
+	r0 := camera.Project(vector.NewVector([]float32{25.0, 0.0, 25.0}))
+	r1 := camera.Project(vector.NewVector([]float32{25.0, 50.0, 25.0}))
+	if math.Abs(float64(r0.Get(0).(float32)-r1.Get(0).(float32))) > 1e-6 || math.Abs(float64(r0.Get(1).(float32)-r1.Get(1).(float32))) > 1e-6 {
+		t.Errorf("Expected %v and %v on the same spot", r0, r1)
+	}
+	if math.Abs(float64(r0.Get(1).(float32))-0.75) > 1e-6 {
+		t.Errorf("Expected y 0.75, got %v", r0)
+	}
+	if r0.Get(2).(float32) >= r1.Get(2).(float32) {
+		t.Errorf("Expected %v in front of %v", r0, r1)
+	}
+}
+
+func Test_UsePreset(t *testing.T) {
+	camera := NewCamera(vector.NewVector([]float32{0.0, -100.0, 0.0}), vector.NewVector([]float32{0.0, 0.0, 0.0}))
+	o := vector.NewVector([]float32{0.0, 0.0, 0.0})
+	x := vector.NewVector([]float32{10.0, 0.0, 0.0})
+	y := vector.NewVector([]float32{0.0, 10.0, 0.0})
+	z := vector.NewVector([]float32{0.0, 0.0, 10.0})
+
+	// Isometric shows all axis with the same length
+	camera.UsePreset(IsometricView)
+	ro := camera.Project(o)
+	lx := screenLength(ro, camera.Project(x))
+	ly := screenLength(ro, camera.Project(y))
+	lz := screenLength(ro, camera.Project(z))
+	if math.Abs(lx-ly) > 1e-5 || math.Abs(lx-lz) > 1e-5 {
+		t.Errorf("Expected equal axis, got x:%f y:%f z:%f", lx, ly, lz)
+	}
+
+	// Cabinet shows the receding axis at half length, up and to the right
+	camera.UsePreset(CabinetView)
+	ro = camera.Project(o)
+	rx := camera.Project(x)
+	ry := camera.Project(y)
+	if math.Abs(screenLength(ro, rx)/2.0-screenLength(ro, ry)) > 1e-5 {
+		t.Errorf("Expected half length receding axis, got x:%v y:%v", rx, ry)
+	}
+	if ry.Get(0).(float32) <= ro.Get(0).(float32) || ry.Get(1).(float32) <= ro.Get(1).(float32) {
+		t.Errorf("Expected receding axis to the top-right, got %v", ry)
+	}
+
+	// Top view has y pointing up on the canvas
+	camera.UsePreset(TopView)
+	ry = camera.Project(y)
+	if math.Abs(float64(ry.Get(0).(float32))-0.5) > 1e-5 || ry.Get(1).(float32) <= 0.5 {
+		t.Errorf("Expected y straight up, got %v", ry)
+	}
+}
+
+// screenLength gives the distance between two projected points, ignoring depth
+func screenLength(a vector.Vector, b vector.Vector) float64 {
+	return math.Hypot(float64(a.Get(0).(float32)-b.Get(0).(float32)), float64(a.Get(1).(float32)-b.Get(1).(float32)))
+}
+
+func Test_DrawMeshAttributes(t *testing.T) {
+	camera := NewCamera(vector.NewVector([]float32{0.0, -100.0, 0.0}), vector.NewVector([]float32{0.0, 0.0, 0.0}))
+	camera.SetProjection(Orthographic)
+	camera.SetViewHeight(10.0)
+	camera.SetAspect(2.0)
+	canvas := NewCanvas(20, 10)
+
+	// A square filling the view, textured red on the left and blue on the right
+	texture := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+	texture.Set(0, 0, color.NRGBA{0xff, 0x00, 0x00, 0xff})
+	texture.Set(1, 0, color.NRGBA{0x00, 0x00, 0xff, 0xff})
+	material := model.NewMaterial(color.White)
+	material.SetTexture(texture)
+	corners := []vector.Vector{
+		vector.NewVector([]float32{-10.0, 0.0, -5.0}), vector.NewVector([]float32{10.0, 0.0, -5.0}),
+		vector.NewVector([]float32{10.0, 0.0, 5.0}), vector.NewVector([]float32{-10.0, 0.0, 5.0}),
+	}
+	uvs := []vector.Vector{
+		vector.NewVector([]float32{0.0, 0.0}), vector.NewVector([]float32{1.0, 0.0}),
+		vector.NewVector([]float32{1.0, 1.0}), vector.NewVector([]float32{0.0, 1.0}),
+	}
+	meshes := []model.Mesh{
+		model.NewMesh([]vector.Vector{corners[0], corners[1], corners[2]}).SetUVs([]vector.Vector{uvs[0], uvs[1], uvs[2]}).SetMaterial(material),
+		model.NewMesh([]vector.Vector{corners[0], corners[2], corners[3]}).SetUVs([]vector.Vector{uvs[0], uvs[2], uvs[3]}).SetMaterial(material),
+	}
+
+	lighting := NewLighting(PhongShading, NewAmbientLight(color.White, 1.0))
+	for _, mesh := range meshes {
+		drawMesh(mesh, camera, camera.Matrix(), lighting, canvas)
+	}
+	if p := canvas.Pixels()[(5*20+2)*4:]; p[0] != 0xff || p[2] != 0x00 {
+		t.Errorf("Expected red on the left, got %v", p[0:3])
+	}
+	if p := canvas.Pixels()[(5*20+17)*4:]; p[0] != 0x00 || p[2] != 0xff {
+		t.Errorf("Expected blue on the right, got %v", p[0:3])
+	}
+
+	// The texture only replaces the diffuse color, the emissive color stays green with any shading
+	glowing := model.NewMaterial(color.White)
+	glowing.SetTexture(texture)
+	glowing.SetEmissive(color.NRGBA{0x00, 0x80, 0x00, 0xff})
+	for _, shading := range []Shading{FlatShading, GouraudShading, PhongShading} {
+		lighting := NewLighting(shading, NewAmbientLight(color.White, 0.5))
+		canvas.Clear()
+		for _, mesh := range meshes {
+			drawMesh(mesh.SetMaterial(glowing), camera, camera.Matrix(), lighting, canvas)
+		}
+		if p := canvas.Pixels()[(5*20+2)*4:]; p[0] != 0x80 || p[1] != 0x80 || p[2] != 0x00 {
+			t.Errorf("Expected half red and green on the left with shading %d, got %v", shading, p[0:3])
+		}
+	}
+
+	// Vertex colors tint the material, also when lighting the corners
+	green := []color.Color{color.NRGBA{0x00, 0xff, 0x00, 0xff}, color.NRGBA{0x00, 0xff, 0x00, 0xff}, color.NRGBA{0x00, 0xff, 0x00, 0xff}}
+	lighting.SetShading(GouraudShading)
+	canvas.Clear()
+	for _, mesh := range meshes {
+		drawMesh(mesh.SetUVs(nil).SetColors(green).SetMaterial(model.NewMaterial(color.White)), camera, camera.Matrix(), lighting, canvas)
+	}
+	if p := canvas.Pixels()[(5*20+10)*4:]; p[0] != 0x00 || p[1] != 0xff || p[2] != 0x00 {
+		t.Errorf("Expected green, got %v", p[0:3])
+	}
 }
@@ -0,0 +1,171 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"log"
+	"math"
+
+	"../model"
+	"../number/vector"
+)
+
+// Shading selects how often the lighting is calculated for a triangle
+type Shading int
+
+const (
+	// FlatShading lights every triangle once, in its center
+	FlatShading Shading = iota
+	// GouraudShading lights the corners of every triangle and blends the colors in between
+	GouraudShading
+	// PhongShading blends the normals accross the triangle and lights every pixel
+	PhongShading
+)
+
+// Lighting combines the lights shining on the model with the way they are applied
+type Lighting struct {
+	shading Shading
+	lights  []Light
+}
+
+// surface holds the properties of a material in the form used for shading
+type surface struct {
+	diffuse   vector.Vector
+	specular  vector.Vector
+	emissive  vector.Vector
+	shininess float64
+	opacity   float32
+}
+
+// newSurface translates a Material for shading
+func newSurface(material *model.Material) surface {
+	return surface{
+		diffuse:   rgb(material.GetDiffuse(), 1.0),
+		specular:  rgb(material.GetSpecular(), 1.0),
+		emissive:  rgb(material.GetEmissive(), 1.0),
+		shininess: float64(material.GetShininess()),
+		opacity:   material.GetOpacity(),
+	}
+}
+
+// NewLighting creates the Lighting for a set of lights
+func NewLighting(shading Shading, lights ...Light) *Lighting {
+	l := &Lighting{}
+	l.SetShading(shading)
+	for _, light := range lights {
+		l.AddLight(light)
+	}
+	return l
+}
+
+// SetShading changes the way the lights are applied
+func (l *Lighting) SetShading(shading Shading) {
+	if shading != FlatShading && shading != GouraudShading && shading != PhongShading {
+		log.Fatalf("Lighting.SetShading: unknown shading %d", shading)
+	}
+	l.shading = shading
+}
+
+// AddLight adds a light to the scene
+func (l *Lighting) AddLight(light Light) {
+	if light == nil {
+		log.Fatalf("Lighting.AddLight: light can't be nil")
+	}
+	l.lights = append(l.lights, light)
+}
+
+// shade calculates the rgb intensity of the surface at position with normal, seen
+// from direction sight (Blinn-Phong). Both sides of the surface are lit the same way.
+func (l *Lighting) shade(position vector.Vector, normal vector.Vector, sight vector.Vector, surface surface) vector.Vector {
+	diffuse, specular := l.light(position, normal, sight, surface)
+	return diffuse.Add(specular)
+}
+
+// light calculates the diffuse and the rest (specular and emissive) of shade apart,
+// so a texture can replace the diffuse color after lighting the corners.
+func (l *Lighting) light(position vector.Vector, normal vector.Vector, sight vector.Vector, surface surface) (vector.Vector, vector.Vector) {
+	if normal.Mulv(sight) < 0.0 {
+		normal = normal.Muls(float32(-1.0))
+	}
+
+	diffuse := vector.NewVector([]float32{0.0, 0.0, 0.0})
+	specular := surface.emissive
+	for _, light := range l.lights {
+		intensity, toLight := light.Illuminate(position)
+		if toLight == nil {
+			diffuse = diffuse.Add(modulate(intensity, surface.diffuse))
+			continue
+		}
+
+		// Diffuse only for the side facing the light
+		facing := normal.Mulv(toLight)
+		if facing <= 0.0 {
+			continue
+		}
+		diffuse = diffuse.Add(modulate(intensity, surface.diffuse).Muls(float32(facing)))
+
+		// Specular highlight where the normal is halfway between the light and the viewer
+		half := toLight.Add(sight)
+		if half.Abs() == 0.0 {
+			continue
+		}
+		highlight := normal.Mulv(half.Unit())
+		if highlight > 0.0 {
+			specular = specular.Add(modulate(intensity, surface.specular).Muls(float32(math.Pow(highlight, surface.shininess))))
+		}
+	}
+
+	return diffuse, specular
+}
+
+// modulate multiplies two rgb intensities element by element
+func modulate(a vector.Vector, b vector.Vector) vector.Vector {
+	return vector.NewVector([]float32{
+		a.Get(0).(float32) * b.Get(0).(float32),
+		a.Get(1).(float32) * b.Get(1).(float32),
+		a.Get(2).(float32) * b.Get(2).(float32),
+	})
+}
+
+// textured combines the lit diffuse and specular parts, with the texture as the diffuse color
+// when there is one. The diffuse part has to be lit with the diffuse color of the texture left
+// out, the texture coordinates are the last two attributes.
+func textured(diffuse vector.Vector, specular vector.Vector, texture image.Image, attributes []float32) vector.Vector {
+	if texture == nil {
+		return diffuse.Add(specular)
+	}
+	return modulate(diffuse, sample(texture, attributes[len(attributes)-2], attributes[len(attributes)-1])).Add(specular)
+}
+
+// sample looks up the rgb intensity of a texture at (u, v), repeating it outside 0..1
+// (0, 0) is the bottom left of the texture.
+func sample(texture image.Image, u float32, v float32) vector.Vector {
+	bounds := texture.Bounds()
+	u -= float32(math.Floor(float64(u)))
+	v -= float32(math.Floor(float64(v)))
+	x := bounds.Min.X + int(u*float32(bounds.Dx()))
+	y := bounds.Min.Y + int((1.0-v)*float32(bounds.Dy()))
+	if x >= bounds.Max.X {
+		x = bounds.Max.X - 1
+	}
+	if y >= bounds.Max.Y {
+		y = bounds.Max.Y - 1
+	}
+	return rgb(texture.At(x, y), 1.0)
+}
+
+// toColor translates an rgb intensity into a color, clipping anything that's too bright
+func toColor(rgb vector.Vector, opacity float32) color.Color {
+	return color.NRGBA{channel(rgb.Get(0).(float32)), channel(rgb.Get(1).(float32)), channel(rgb.Get(2).(float32)), channel(opacity)}
+}
+
+// channel translates an intensity into a color channel
+func channel(i float32) byte {
+	switch {
+	case i <= 0.0:
+		return 0x00
+	case i >= 1.0:
+		return 0xff
+	}
+	return byte(i*255.0 + 0.5)
+}